@@ -0,0 +1,218 @@
+package dynamic
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Style
+// 单元格样式类型，由后端映射为具体的样式
+type Style int
+
+const (
+	// HeaderStyle
+	// 表头样式
+	HeaderStyle Style = iota
+
+	// BodyStyle
+	// 数据样式
+	BodyStyle
+
+	// FooterStyle
+	// 汇总行样式
+	FooterStyle
+)
+
+// Validation
+// 单元格数据验证
+type Validation struct {
+	// List
+	// 下拉列表的可选值
+	List []string
+
+	// Min
+	// 数值的最小值，List为空时生效
+	Min float64
+
+	// Max
+	// 数值的最大值，List为空时生效
+	Max float64
+}
+
+// Backend
+// 渲染后端，渲染器通过后端写入单元格
+// 所有坐标都从1开始，X是列，Y是行
+type Backend interface {
+	// MergeCell
+	// 合并区域内的单元格
+	MergeCell(startX, startY, endX, endY int) error
+
+	// SetValue
+	// 设置单元格的值
+	SetValue(x, y int, value any) error
+
+	// SetFormula
+	// 设置单元格的公式，公式不以"="开头，单元格中已有的值作为缓存的计算结果
+	SetFormula(x, y int, formula string) error
+
+	// SetStyle
+	// 设置区域内单元格的样式
+	SetStyle(startX, startY, endX, endY int, style Style) error
+
+	// SetColWidth
+	// 设置列宽
+	SetColWidth(startX, endX int, width float64) error
+
+	// AddValidation
+	// 为区域内的单元格添加数据验证
+	AddValidation(startX, startY, endX, endY int, validation Validation) error
+
+	// Clear
+	// 清空区域内单元格的值、公式和样式，合并、列宽和数据验证不变
+	Clear(startX, startY, endX, endY int) error
+}
+
+// ExcelizeBackend
+// 基于excelize的渲染后端，也是渲染器的默认后端
+type ExcelizeBackend struct {
+	file   *excelize.File
+	sheet  string
+	styles *styles
+
+	// ready
+	// sheet是否已经创建
+	ready bool
+}
+
+// NewExcelizeBackend
+// 创建excelize后端，sheet在第一次写入时创建
+func NewExcelizeBackend(file *excelize.File, sheet string, theme Theme) *ExcelizeBackend {
+	return &ExcelizeBackend{
+		file:   file,
+		sheet:  sheet,
+		styles: newStyles(theme),
+	}
+}
+
+// File
+// 获取后端写入的excel文件
+func (b *ExcelizeBackend) File() *excelize.File {
+	return b.file
+}
+
+// Sheet
+// 获取后端写入的sheet名称
+func (b *ExcelizeBackend) Sheet() string {
+	return b.sheet
+}
+
+func (b *ExcelizeBackend) prepare() error {
+	if b.ready {
+		return nil
+	}
+
+	if _, err := b.file.NewSheet(b.sheet); err != nil {
+		return err
+	}
+	b.ready = true
+
+	return nil
+}
+
+func (b *ExcelizeBackend) MergeCell(startX, startY, endX, endY int) error {
+	if err := b.prepare(); err != nil {
+		return err
+	}
+
+	return b.file.MergeCell(b.sheet, cellName(startX, startY), cellName(endX, endY))
+}
+
+func (b *ExcelizeBackend) SetValue(x, y int, value any) error {
+	if err := b.prepare(); err != nil {
+		return err
+	}
+
+	return b.file.SetCellValue(b.sheet, cellName(x, y), value)
+}
+
+func (b *ExcelizeBackend) SetFormula(x, y int, formula string) error {
+	if err := b.prepare(); err != nil {
+		return err
+	}
+
+	return b.file.SetCellFormula(b.sheet, cellName(x, y), formula)
+}
+
+func (b *ExcelizeBackend) SetStyle(startX, startY, endX, endY int, style Style) error {
+	if err := b.prepare(); err != nil {
+		return err
+	}
+
+	header, body, footer, err := b.styles.ids(b.file)
+	if err != nil {
+		return err
+	}
+
+	styleId := body
+	switch style {
+	case HeaderStyle:
+		styleId = header
+	case FooterStyle:
+		styleId = footer
+	}
+
+	return b.file.SetCellStyle(b.sheet, cellName(startX, startY), cellName(endX, endY), styleId)
+}
+
+func (b *ExcelizeBackend) SetColWidth(startX, endX int, width float64) error {
+	if err := b.prepare(); err != nil {
+		return err
+	}
+
+	return b.file.SetColWidth(b.sheet, numberToLetters(startX), numberToLetters(endX), width)
+}
+
+func (b *ExcelizeBackend) AddValidation(startX, startY, endX, endY int, validation Validation) error {
+	if err := b.prepare(); err != nil {
+		return err
+	}
+
+	dv := excelize.NewDataValidation(true)
+	dv.SetSqref(fmt.Sprintf("%s:%s", cellName(startX, startY), cellName(endX, endY)))
+
+	var err error
+	if len(validation.List) > 0 {
+		err = dv.SetDropList(validation.List)
+	} else {
+		err = dv.SetRange(validation.Min, validation.Max, excelize.DataValidationTypeDecimal, excelize.DataValidationOperatorBetween)
+	}
+	if err != nil {
+		return err
+	}
+
+	return b.file.AddDataValidation(b.sheet, dv)
+}
+
+func (b *ExcelizeBackend) Clear(startX, startY, endX, endY int) error {
+	if err := b.prepare(); err != nil {
+		return err
+	}
+
+	// 设置空值时excelize同时删除单元格的公式
+	for y := startY; y <= endY; y++ {
+		for x := startX; x <= endX; x++ {
+			if err := b.file.SetCellValue(b.sheet, cellName(x, y), nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return b.file.SetCellStyle(b.sheet, cellName(startX, startY), cellName(endX, endY), 0)
+}
+
+// cellName
+// 将坐标转换为excel单元格名称，例如(1, 1)转换为A1
+func cellName(x, y int) string {
+	return fmt.Sprintf("%s%d", numberToLetters(x), y)
+}
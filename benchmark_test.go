@@ -0,0 +1,149 @@
+package dynamic
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// benchRow
+// 基准测试使用的宽表，13列，3层表头
+type benchRow struct {
+	ID      int    `xlsx:"col:编号"`
+	Name    string `xlsx:"col:姓名"`
+	Class   string `xlsx:"col:班级"`
+	Contact struct {
+		Phone string `xlsx:"col:电话"`
+		Email string `xlsx:"col:邮箱"`
+	} `xlsx:"col:联系方式"`
+	Score struct {
+		Chinese struct {
+			Normal  float64 `xlsx:"col:平时"`
+			Examing float64 `xlsx:"col:考试"`
+		} `xlsx:"col:语文"`
+		Math struct {
+			Normal  float64 `xlsx:"col:平时"`
+			Examing float64 `xlsx:"col:考试"`
+		} `xlsx:"col:数学"`
+	} `xlsx:"col:成绩"`
+	Rank   uint   `xlsx:"col:排名"`
+	Active bool   `xlsx:"col:在读"`
+	Memo   string `xlsx:"col:备注"`
+}
+
+func benchRows(n int) []benchRow {
+	rows := make([]benchRow, n)
+	for i := range rows {
+		row := &rows[i]
+		row.ID = i + 1
+		row.Name = fmt.Sprintf("学生%d", i)
+		row.Class = fmt.Sprintf("%d班", i%10+1)
+		row.Contact.Phone = fmt.Sprintf("138%08d", i)
+		row.Contact.Email = fmt.Sprintf("s%d@example.com", i)
+		row.Score.Chinese.Normal = float64(i % 100)
+		row.Score.Chinese.Examing = float64((i * 7) % 100)
+		row.Score.Math.Normal = float64((i * 3) % 100)
+		row.Score.Math.Examing = float64((i * 11) % 100)
+		row.Rank = uint(i + 1)
+		row.Active = i%2 == 0
+		row.Memo = "-"
+	}
+
+	return rows
+}
+
+func BenchmarkRowValues(b *testing.B) {
+	rows := benchRows(1000)
+	tree := (&Parser[benchRow]{}).Tree()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, row := range rows {
+			tree.RowValues(row)
+		}
+	}
+}
+
+func BenchmarkRender(b *testing.B) {
+	rows := benchRows(1000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := RenderGrid(rows); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRead(b *testing.B) {
+	file := excelize.NewFile()
+	renderer, err := NewRenderer[benchRow](file, "Sheet1")
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := renderer.Render(benchRows(1000)); err != nil {
+		b.Fatal(err)
+	}
+	reader, err := NewReader[benchRow]()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		values, err := reader.Read(file, "Sheet1")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(values) != 1000 {
+			b.Fatalf("read %d rows, want 1000", len(values))
+		}
+	}
+}
+
+// benchLeaf
+// 4个叶子节点的分组，用于构造很宽的表头
+type benchLeaf struct {
+	A int
+	B int
+	C struct {
+		D int
+		E int
+	}
+}
+
+// wideType
+// 由150个benchLeaf字段组成的结构体，共600个叶子节点
+func wideType() reflect.Type {
+	fields := make([]reflect.StructField, 150)
+	for i := range fields {
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("F%03d", i),
+			Type: reflect.TypeOf(benchLeaf{}),
+		}
+	}
+
+	return reflect.StructOf(fields)
+}
+
+func BenchmarkParseWide(b *testing.B) {
+	typeOf := wideType()
+	parser := &Parser[any]{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree, err := parser.parse(typeOf)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if leaves := len(tree.Leaves()); leaves != 600 {
+			b.Fatalf("parsed %d leaves, want 600", leaves)
+		}
+	}
+}
@@ -0,0 +1,615 @@
+// dynamicgen
+// 根据xlsx标签为结构体生成不依赖反射的EncodeRow、DecodeRow和HeaderLayout方法
+//
+// 用法:
+//
+//	//go:generate go run github.com/infinitete/dynamic/cmd/dynamicgen -type Book,Student
+//
+// Renderer和Reader会在静态布局和解析树一致时自动使用生成的方法
+// 字段使用其他包中声明的类型时无法从源码解析，该类型不生成方法，Renderer和Reader使用反射
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const importPath = "github.com/infinitete/dynamic"
+
+var (
+	typeNames = flag.String("type", "", "comma-separated list of struct type names; must be set")
+	output    = flag.String("output", "", "output file name; default <first type>_dynamic.go")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("dynamicgen: ")
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+
+	if err := run(dir, *typeNames, *output); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run
+// 为dir中的类型生成代码，output为空时使用<第一个类型>_dynamic.go，相对路径基于dir
+func run(dir string, typeNames string, output string) error {
+	types := strings.Split(typeNames, ",")
+	out := output
+	if out == "" {
+		out = strings.ToLower(types[0]) + "_dynamic.go"
+	}
+	if !filepath.IsAbs(out) {
+		out = filepath.Join(dir, out)
+	}
+
+	pkg, err := loadPackage(dir, out)
+	if err != nil {
+		return err
+	}
+
+	g := generator{pkg: pkg, typeNames: typeNames}
+	generated := 0
+	for _, name := range types {
+		err := g.generate(strings.TrimSpace(name))
+		var external *externalError
+		if errors.As(err, &external) {
+			// 其他包中的类型无法从源码解析，该类型不生成方法，运行时使用反射
+			log.Printf("skip type %s: %v, it falls back to reflection", name, err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		generated++
+	}
+
+	if generated == 0 {
+		if err := os.Remove(out); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	src, err := g.source()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(out, src, 0644)
+}
+
+// externalError
+// 字段使用了其他包中声明的类型
+type externalError struct {
+	owner string
+	field string
+	typ   string
+}
+
+func (e *externalError) Error() string {
+	return fmt.Sprintf("type %s: field %s uses type %s declared in another package", e.owner, e.field, e.typ)
+}
+
+// pkg
+// 解析后的包，只保留类型声明
+type pkg struct {
+	name  string
+	types map[string]ast.Expr
+}
+
+func loadPackage(dir string, out string) (*pkg, error) {
+	fset := token.NewFileSet()
+	skip := filepath.Base(out)
+	packages, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != skip
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	name := os.Getenv("GOPACKAGE")
+	if name == "" {
+		if len(packages) != 1 {
+			return nil, fmt.Errorf("%d packages found in %s, run it with go generate", len(packages), dir)
+		}
+		for n := range packages {
+			name = n
+		}
+	}
+
+	astPkg, ok := packages[name]
+	if !ok {
+		return nil, fmt.Errorf("package %s not found in %s", name, dir)
+	}
+
+	p := &pkg{name: name, types: map[string]ast.Expr{}}
+	for _, file := range astPkg.Files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				p.types[typeSpec.Name.Name] = typeSpec.Type
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// node
+// 和dynamic.Node对应的节点
+type node struct {
+	field    string
+	path     string
+	title    string
+	kind     reflect.Kind
+	typ      string
+	level    int
+	children []*node
+
+	startX int
+	endX   int
+
+	// depth
+	// 字段所在的匿名结构体层数，tagged表示设置了col标签，用于处理同名字段
+	depth  int
+	tagged bool
+
+	// readOnly
+	// 计算列和未导出的字段只渲染，DecodeRow中跳过
+	readOnly bool
+}
+
+func (n *node) cols() int {
+	if len(n.children) == 0 {
+		return 1
+	}
+
+	cols := 0
+	for _, child := range n.children {
+		cols += child.cols()
+	}
+	return cols
+}
+
+var kinds = map[string]reflect.Kind{
+	"string":  reflect.String,
+	"bool":    reflect.Bool,
+	"int":     reflect.Int,
+	"int8":    reflect.Int8,
+	"int16":   reflect.Int16,
+	"int32":   reflect.Int32,
+	"int64":   reflect.Int64,
+	"uint":    reflect.Uint,
+	"uint8":   reflect.Uint8,
+	"uint16":  reflect.Uint16,
+	"uint32":  reflect.Uint32,
+	"uint64":  reflect.Uint64,
+	"float32": reflect.Float32,
+	"float64": reflect.Float64,
+}
+
+type generator struct {
+	pkg       *pkg
+	typeNames string
+	buf       bytes.Buffer
+	strconv   bool
+}
+
+// parseStruct
+// 和dynamic.Parser.parseType保持一致的解析规则
+func (g *generator) parseStruct(name string, st *ast.StructType, level int, readOnly bool) ([]*node, error) {
+	nodes, err := g.parseFields(name, st, level, readOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	return dominantNodes(nodes), nil
+}
+
+// parseFields
+// 解析结构体的所有字段，没有col标签的匿名结构体字段展开到当前层级
+func (g *generator) parseFields(name string, st *ast.StructType, level int, readOnly bool) ([]*node, error) {
+	var nodes []*node
+	for _, field := range st.Fields.List {
+		var tag string
+		if field.Tag != nil {
+			raw, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tag = reflect.StructTag(raw).Get("xlsx")
+		}
+		if tag == "-" {
+			continue
+		}
+
+		var title, method string
+		extra, include := false, false
+		for _, c := range strings.Split(tag, ",") {
+			key, value, _ := strings.Cut(c, ":")
+			switch key {
+			case "col":
+				title = value
+			case "extra":
+				extra = true
+			case "include":
+				include = true
+			case "method":
+				method = value
+			}
+		}
+		if extra {
+			continue
+		}
+
+		names := make([]string, 0, len(field.Names))
+		for _, ident := range field.Names {
+			names = append(names, ident.Name)
+		}
+		if len(names) == 0 {
+			// 匿名字段的字段名是类型名
+			typeName := embeddedName(field.Type)
+			if typeName == "" {
+				return nil, fmt.Errorf("type %s: unsupported embedded field %s", name, exprString(field.Type))
+			}
+			names = append(names, typeName)
+
+			// 只有当前包中声明的结构体可以展开，指针和其他包的类型按普通字段处理
+			var declared *ast.StructType
+			embedded := false
+			if ident, ok := field.Type.(*ast.Ident); ok {
+				declared, embedded = g.pkg.types[ident.Name].(*ast.StructType)
+			}
+			if !ast.IsExported(typeName) && !embedded && !include && method == "" {
+				continue
+			}
+			if embedded && title == "" {
+				promoted, err := g.parseFields(typeName, declared, level, readOnly)
+				if err != nil {
+					return nil, err
+				}
+				for _, n := range promoted {
+					n.path = typeName + "." + n.path
+					n.depth++
+					nodes = append(nodes, n)
+				}
+				continue
+			}
+		}
+
+		for _, fieldName := range names {
+			// 未导出的字段默认忽略
+			exported := ast.IsExported(fieldName)
+			if !exported && len(field.Names) > 0 && !include && method == "" {
+				continue
+			}
+
+			n := &node{
+				field:    fieldName,
+				path:     fieldName,
+				title:    title,
+				level:    level,
+				tagged:   title != "",
+				readOnly: readOnly || (!exported && len(field.Names) > 0),
+			}
+			if n.title == "" {
+				n.title = fieldName
+			}
+			if method != "" {
+				// 计算列通过所在结构体的方法获取值
+				n.path = method + "()"
+				n.readOnly = true
+				nodes = append(nodes, n)
+				continue
+			}
+			if err := g.resolve(name, n, field.Type); err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, n)
+		}
+	}
+
+	return nodes, nil
+}
+
+// embeddedName
+// 匿名字段的字段名，即去掉指针和包名后的类型名，不支持的类型返回空字符串
+func embeddedName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	}
+
+	return ""
+}
+
+// dominantNodes
+// 和dynamic中的规则一致：匿名结构体层数最少的字段优先，层数相同时只有一个设置了col标签的字段优先，否则都忽略
+func dominantNodes(fields []*node) []*node {
+	var groups = map[string][]*node{}
+	for _, n := range fields {
+		groups[n.field] = append(groups[n.field], n)
+	}
+
+	var dominant = map[*node]bool{}
+	for _, group := range groups {
+		var candidates []*node
+		for _, n := range group {
+			if len(candidates) == 0 || n.depth < candidates[0].depth {
+				candidates = []*node{n}
+			} else if n.depth == candidates[0].depth {
+				candidates = append(candidates, n)
+			}
+		}
+
+		if len(candidates) == 1 {
+			dominant[candidates[0]] = true
+			continue
+		}
+
+		var tagged []*node
+		for _, n := range candidates {
+			if n.tagged {
+				tagged = append(tagged, n)
+			}
+		}
+		if len(tagged) == 1 {
+			dominant[tagged[0]] = true
+		}
+	}
+
+	nodes := []*node{}
+	for _, n := range fields {
+		if dominant[n] {
+			nodes = append(nodes, n)
+		}
+	}
+
+	return nodes
+}
+
+// resolve
+// 解析字段类型，结构体解析为子节点，基础类型记录kind
+func (g *generator) resolve(owner string, n *node, expr ast.Expr) error {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return fmt.Errorf("type %s must not contain pointer field %s", owner, n.field)
+	case *ast.SelectorExpr:
+		return &externalError{owner: owner, field: n.field, typ: exprString(t)}
+	case *ast.StructType:
+		children, err := g.parseStruct(owner, t, n.level+1, n.readOnly)
+		if err != nil {
+			return err
+		}
+		n.kind = reflect.Struct
+		n.children = children
+		return nil
+	case *ast.Ident:
+		if kind, ok := kinds[t.Name]; ok {
+			n.kind = kind
+			n.typ = t.Name
+			return nil
+		}
+
+		declared, ok := g.pkg.types[t.Name]
+		if !ok {
+			return fmt.Errorf("type %s: type %s of field %s is not declared in package %s", owner, t.Name, n.field, g.pkg.name)
+		}
+		if st, ok := declared.(*ast.StructType); ok {
+			children, err := g.parseStruct(t.Name, st, n.level+1, n.readOnly)
+			if err != nil {
+				return err
+			}
+			n.kind = reflect.Struct
+			n.children = children
+			return nil
+		}
+		if err := g.resolve(owner, n, declared); err != nil {
+			return err
+		}
+		// 基于基础类型定义的类型需要转换
+		n.typ = t.Name
+		return nil
+	}
+
+	return fmt.Errorf("type %s: unsupported type %s of field %s", owner, exprString(expr), n.field)
+}
+
+func (g *generator) generate(name string) error {
+	declared, ok := g.pkg.types[name]
+	if !ok {
+		return fmt.Errorf("type %s is not declared in package %s", name, g.pkg.name)
+	}
+	st, ok := declared.(*ast.StructType)
+	if !ok {
+		return fmt.Errorf("type %s is not struct", name)
+	}
+
+	nodes, err := g.parseStruct(name, st, 1, false)
+	if err != nil {
+		return err
+	}
+
+	// 和dynamic.Tree.Metas一致的先序遍历
+	var metas []*node
+	var walk func([]*node, int)
+	walk = func(nodes []*node, startX int) {
+		for _, n := range nodes {
+			n.startX = startX
+			n.endX = startX + n.cols() - 1
+			metas = append(metas, n)
+			walk(n.children, startX)
+			startX = n.endX + 1
+		}
+	}
+	walk(nodes, 1)
+
+	maxLevel := 0
+	for _, n := range metas {
+		if n.level > maxLevel {
+			maxLevel = n.level
+		}
+	}
+
+	type leaf struct {
+		*node
+		path string
+	}
+	var leaves []leaf
+	var collect func([]*node, string)
+	collect = func(nodes []*node, prefix string) {
+		for _, n := range nodes {
+			if len(n.children) == 0 {
+				leaves = append(leaves, leaf{node: n, path: prefix + "." + n.path})
+				continue
+			}
+			collect(n.children, prefix+"."+n.path)
+		}
+	}
+	collect(nodes, "v")
+
+	fmt.Fprintf(&g.buf, "// EncodeRow\n// 按列顺序返回%s一行的值\n", name)
+	fmt.Fprintf(&g.buf, "func (v %s) EncodeRow() []any {\n\treturn []any{\n", name)
+	for _, l := range leaves {
+		fmt.Fprintf(&g.buf, "\t\t%s,\n", l.path)
+	}
+	fmt.Fprintf(&g.buf, "\t}\n}\n\n")
+
+	fmt.Fprintf(&g.buf, "// DecodeRow\n// 将按列顺序排列的单元格转换为%s\n", name)
+	fmt.Fprintf(&g.buf, "func (%s) DecodeRow(values []string) (%s, error) {\n", name, name)
+	fmt.Fprintf(&g.buf, "\tvar v %s\n\tvar errs dynamic.ColumnErrors\n", name)
+	for i, l := range leaves {
+		if l.readOnly {
+			continue
+		}
+		g.decodeField(i, l.path, l.node)
+	}
+	fmt.Fprintf(&g.buf, "\n\treturn v, errs.Err()\n}\n\n")
+
+	fmt.Fprintf(&g.buf, "// HeaderLayout\n// %s的静态表头布局\n", name)
+	fmt.Fprintf(&g.buf, "func (%s) HeaderLayout() []dynamic.HeaderCell {\n\treturn []dynamic.HeaderCell{\n", name)
+	for _, n := range metas {
+		endY := n.level
+		if len(n.children) == 0 {
+			endY = maxLevel
+		}
+		fmt.Fprintf(&g.buf, "\t\t{Title: %s, StartX: %d, StartY: %d, EndX: %d, EndY: %d},\n", strconv.Quote(n.title), n.startX, n.level, n.endX, endY)
+	}
+	fmt.Fprintf(&g.buf, "\t}\n}\n\n")
+
+	return nil
+}
+
+// decodeField
+// 生成一列的转换代码，和dynamic.Reader.setStructValue的转换规则一致
+func (g *generator) decodeField(i int, path string, n *node) {
+	fmt.Fprintf(&g.buf, "\tif len(values) > %d && values[%d] != \"\" {\n", i, i)
+
+	switch n.kind {
+	case reflect.String:
+		if n.typ == "string" {
+			fmt.Fprintf(&g.buf, "\t\t%s = values[%d]\n", path, i)
+		} else {
+			fmt.Fprintf(&g.buf, "\t\t%s = %s(values[%d])\n", path, n.typ, i)
+		}
+		fmt.Fprintf(&g.buf, "\t}\n")
+		return
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fmt.Fprintf(&g.buf, "\t\tn, err := strconv.ParseInt(values[%d], 10, %d)\n", i, bitSize(n.kind))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		fmt.Fprintf(&g.buf, "\t\tn, err := strconv.ParseUint(values[%d], 10, %d)\n", i, bitSize(n.kind))
+	case reflect.Float32, reflect.Float64:
+		fmt.Fprintf(&g.buf, "\t\tn, err := strconv.ParseFloat(values[%d], %d)\n", i, bitSize(n.kind))
+	case reflect.Bool:
+		fmt.Fprintf(&g.buf, "\t\tn, err := strconv.ParseBool(values[%d])\n", i)
+	}
+	g.strconv = true
+
+	fmt.Fprintf(&g.buf, "\t\tif err != nil {\n")
+	fmt.Fprintf(&g.buf, "\t\t\terrs = append(errs, &dynamic.ColumnError{Index: %d, Err: err})\n", i)
+	fmt.Fprintf(&g.buf, "\t\t} else {\n\t\t\t%s = %s(n)\n\t\t}\n\t}\n", path, n.typ)
+}
+
+func bitSize(kind reflect.Kind) int {
+	switch kind {
+	case reflect.Int8, reflect.Uint8:
+		return 8
+	case reflect.Int16, reflect.Uint16:
+		return 16
+	case reflect.Int32, reflect.Uint32, reflect.Float32:
+		return 32
+	case reflect.Int64, reflect.Uint64, reflect.Float64:
+		return 64
+	}
+
+	return 0
+}
+
+func (g *generator) source() ([]byte, error) {
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by dynamicgen -type %s; DO NOT EDIT.\n\n", g.typeNames)
+	fmt.Fprintf(&src, "package %s\n\n", g.pkg.name)
+	src.WriteString("import (\n")
+	if g.strconv {
+		src.WriteString("\t\"strconv\"\n\n")
+	}
+	fmt.Fprintf(&src, "\t%q\n)\n\n", importPath)
+	src.Write(g.buf.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated source: %w\n%s", err, src.Bytes())
+	}
+
+	return formatted, nil
+}
+
+func exprString(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return exprString(t.X) + "." + t.Sel.Name
+	case *ast.StarExpr:
+		return "*" + exprString(t.X)
+	case *ast.ArrayType:
+		return "[]" + exprString(t.Elt)
+	case *ast.MapType:
+		return "map[" + exprString(t.Key) + "]" + exprString(t.Value)
+	}
+
+	return fmt.Sprintf("%T", expr)
+}
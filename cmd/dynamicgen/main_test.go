@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestBookInSync
+// examples/book中提交的生成代码和重新生成的结果一致
+func TestBookInSync(t *testing.T) {
+	dir := filepath.Join("..", "..", "examples", "book")
+	out := filepath.Join(t.TempDir(), "book_dynamic.go")
+
+	// 输出文件和提交的文件同名，生成时会跳过提交的文件
+	if err := run(dir, "Book,Student", out); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join(dir, "book_dynamic.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("examples/book/book_dynamic.go is out of date, run go generate in examples/book")
+	}
+}
+
+// TestFixture
+// 为testdata/fixture生成代码，在临时模块中运行fixture的测试
+// 比较生成的EncodeRow、DecodeRow、HeaderLayout和反射的结果
+func TestFixture(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a temporary module")
+	}
+
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"fixture.go", "fixture_test.go"} {
+		copyFile(t, filepath.Join("testdata", "fixture", name), filepath.Join(dir, name))
+	}
+	copyFile(t, filepath.Join(root, "go.sum"), filepath.Join(dir, "go.sum"))
+
+	// 使用和本模块相同的依赖版本，dynamic指向本地的源码
+	mod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gomod := strings.Replace(string(mod), "module github.com/infinitete/dynamic", "module fixture", 1)
+	gomod += "\nrequire github.com/infinitete/dynamic v0.0.0\n\nreplace github.com/infinitete/dynamic => " + root + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run(dir, "Model", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("go", "test", "-mod=mod", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "GOPROXY=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go test in %s: %v\n%s", dir, err, output)
+	}
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
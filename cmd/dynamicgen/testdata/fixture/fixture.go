@@ -0,0 +1,67 @@
+// fixture
+// dynamicgen测试使用的类型，覆盖生成器支持的所有字段写法
+package fixture
+
+import "fmt"
+
+type Level int8
+
+type Contact struct {
+	Phone string `xlsx:"col:电话"`
+	Email string `xlsx:"col:邮箱,optional"`
+}
+
+type Audit struct {
+	Creator string `xlsx:"col:创建人"`
+	Version uint16 `xlsx:"col:版本"`
+}
+
+type Course struct {
+	Normal  int8    `xlsx:"col:平时"`
+	Examing float32 `xlsx:"col:考试"`
+	Passed  bool    `xlsx:"col:通过"`
+	Grade   string  `xlsx:"col:等级,method:Grading"`
+	Final   float64 `xlsx:"col:最终,formula:ROUND({Normal}*0.3+{Examing}*0.7,0)"`
+}
+
+func (c Course) Grading() string {
+	if c.Final >= 60 {
+		return "合格"
+	}
+	return "不合格"
+}
+
+type Model struct {
+	Ignored string  `xlsx:"-"`
+	ID      uint32  `xlsx:"col:编号"`
+	Name    string  `xlsx:"col:姓名"`
+	Level   Level   `xlsx:"col:级别"`
+	Contact Contact `xlsx:"col:联系方式"`
+	Audit
+	Score struct {
+		Chinese Course `xlsx:"col:语文"`
+		Math    Course `xlsx:"col:数学"`
+	} `xlsx:"col:成绩"`
+	Total int64  `xlsx:"col:总分,agg:sum"`
+	note  string `xlsx:"col:备注,include"`
+}
+
+// Models
+// 测试数据
+func Models(n int) []Model {
+	models := make([]Model, n)
+	for i := range models {
+		m := &models[i]
+		m.ID = uint32(i + 1)
+		m.Name = fmt.Sprintf("学生%d", i)
+		m.Level = Level(i%3 - 1)
+		m.Contact = Contact{Phone: fmt.Sprintf("138%04d", i), Email: fmt.Sprintf("s%d@example.com", i)}
+		m.Audit = Audit{Creator: "admin", Version: uint16(i)}
+		m.Score.Chinese = Course{Normal: int8(50 + i), Examing: 60.5 + float32(i), Passed: i%2 == 0, Final: float64(58 + i)}
+		m.Score.Math = Course{Normal: int8(90 - i), Examing: 70.25, Passed: true, Final: 75}
+		m.Total = int64(m.Score.Chinese.Final + m.Score.Math.Final)
+		m.note = fmt.Sprintf("备注%d", i)
+	}
+
+	return models
+}
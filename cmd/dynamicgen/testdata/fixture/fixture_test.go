@@ -0,0 +1,117 @@
+package fixture
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/infinitete/dynamic"
+	"github.com/xuri/excelize/v2"
+)
+
+// plainModel
+// 和Model字段相同但是没有生成的方法，读写使用反射
+type plainModel Model
+
+func TestLayout(t *testing.T) {
+	tree := (&dynamic.Parser[plainModel]{}).Tree()
+	if got, want := (Model{}).HeaderLayout(), tree.Layout(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("generated layout %v, want %v", got, want)
+	}
+}
+
+func TestEncodeRow(t *testing.T) {
+	tree := (&dynamic.Parser[plainModel]{}).Tree()
+	for i, m := range Models(5) {
+		if got, want := m.EncodeRow(), tree.RowValues(plainModel(m)); !reflect.DeepEqual(got, want) {
+			t.Errorf("row %d: EncodeRow %#v, want %#v", i, got, want)
+		}
+	}
+}
+
+// render
+// 使用反射渲染测试数据，cells中的单元格在渲染后覆盖
+func render(t *testing.T, cells map[string]string) *excelize.File {
+	t.Helper()
+
+	models := Models(5)
+	plains := make([]plainModel, len(models))
+	for i, m := range models {
+		plains[i] = plainModel(m)
+	}
+
+	file := excelize.NewFile()
+	renderer, err := dynamic.NewRenderer[plainModel](file, "Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := renderer.Render(plains); err != nil {
+		t.Fatal(err)
+	}
+	for cell, value := range cells {
+		if err := file.SetCellStr("Sheet1", cell, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return file
+}
+
+// read
+// 分别使用生成的DecodeRow和反射读取
+func read(t *testing.T, file *excelize.File) (generated []Model, generatedErr error, plain []Model, plainErr error) {
+	t.Helper()
+
+	reader, err := dynamic.NewReader[Model]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated, generatedErr = reader.Read(file, "Sheet1")
+
+	plainReader, err := dynamic.NewReader[plainModel]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	plains, plainErr := plainReader.Read(file, "Sheet1")
+	for _, p := range plains {
+		plain = append(plain, Model(p))
+	}
+
+	return generated, generatedErr, plain, plainErr
+}
+
+func TestDecodeRow(t *testing.T) {
+	generated, generatedErr, plain, plainErr := read(t, render(t, nil))
+	if generatedErr != nil || plainErr != nil {
+		t.Fatalf("generated error %v, reflection error %v", generatedErr, plainErr)
+	}
+	if !reflect.DeepEqual(generated, plain) {
+		t.Fatalf("DecodeRow %+v, want %+v", generated, plain)
+	}
+
+	// 未导出的字段只渲染，读取时都保持零值
+	want := Models(5)
+	for i := range want {
+		want[i].note = ""
+	}
+	if !reflect.DeepEqual(generated, want) {
+		t.Errorf("read %+v, want %+v", generated, want)
+	}
+}
+
+func TestDecodeRowErrors(t *testing.T) {
+	for _, cells := range []map[string]string{
+		// 语文/平时是int8
+		{"H5": "300"},
+		// 编号是uint32
+		{"A4": "4294967296"},
+		// 语文/通过是bool
+		{"J6": "maybe"},
+		// 数学/考试是float32
+		{"N4": "1e40"},
+	} {
+		_, generatedErr, _, plainErr := read(t, render(t, cells))
+		if generatedErr == nil || plainErr == nil {
+			t.Errorf("%v: generated error %v, reflection error %v, want both to fail", cells, generatedErr, plainErr)
+		}
+	}
+}
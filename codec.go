@@ -0,0 +1,121 @@
+package dynamic
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RowEncoder
+// 按列顺序返回一行的值，由dynamicgen生成，避免渲染时使用反射
+type RowEncoder interface {
+	EncodeRow() []any
+}
+
+// RowDecoder
+// 将按列顺序排列的单元格转换为结构体，由dynamicgen生成，避免读取时使用反射
+// 空字符串表示单元格为空，对应的字段保留零值
+type RowDecoder[T any] interface {
+	DecodeRow(values []string) (T, error)
+}
+
+// HeaderLayouter
+// 静态的表头布局，由dynamicgen生成
+// 只有静态布局和解析树一致时，才会使用生成的EncodeRow和DecodeRow
+type HeaderLayouter interface {
+	HeaderLayout() []HeaderCell
+}
+
+// HeaderCell
+// 表头中一个节点所占的区域
+type HeaderCell struct {
+	Title  string
+	StartX int
+	StartY int
+	EndX   int
+	EndY   int
+}
+
+// ColumnError
+// 生成的DecodeRow中某一列转换失败，Index是列在叶子节点中的序号
+type ColumnError struct {
+	Index int
+	Err   error
+}
+
+func (e *ColumnError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Index, e.Err.Error())
+}
+
+func (e *ColumnError) Unwrap() error {
+	return e.Err
+}
+
+// ColumnErrors
+// 生成的DecodeRow中所有转换失败的列
+type ColumnErrors []*ColumnError
+
+func (e ColumnErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Err
+// 没有错误时返回nil，供生成的代码使用
+func (e ColumnErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// codec
+// 类型T上生成的编解码方法
+type codec[T any] struct {
+	checked bool
+	encoder bool
+	decoder RowDecoder[T]
+}
+
+// Layout
+// 获取解析树的表头布局，顺序和Metas一致
+func (t *Tree[T]) Layout() []HeaderCell {
+	metas := t.Metas()
+	layout := make([]HeaderCell, len(metas))
+	for i, meta := range metas {
+		layout[i] = HeaderCell{
+			Title:  meta.Node.Title,
+			StartX: meta.StartX,
+			StartY: meta.StartY,
+			EndX:   meta.EndX,
+			EndY:   meta.EndY,
+		}
+	}
+
+	return layout
+}
+
+// generated
+// 检查类型T是否有生成的编解码方法，并且静态布局和解析树一致
+func (t *Tree[T]) generated() *codec[T] {
+	if t.codec.checked {
+		return &t.codec
+	}
+	t.codec.checked = true
+
+	var zero T
+	layouter, ok := any(zero).(HeaderLayouter)
+	if !ok || !reflect.DeepEqual(layouter.HeaderLayout(), t.Layout()) {
+		return &t.codec
+	}
+
+	_, t.codec.encoder = any(zero).(RowEncoder)
+	t.codec.decoder, _ = any(zero).(RowDecoder[T])
+
+	return &t.codec
+}
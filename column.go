@@ -0,0 +1,161 @@
+package dynamic
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Column
+// 渲染时插入的计算列，不需要在结构体中定义字段
+// 计算列只渲染，读取时不会和结构体匹配，类型中有extra字段时会写入extra
+type Column[T any] struct {
+	// Title
+	// 列标题
+	Title string
+
+	// Parent
+	// 所在分组从上到下的标题路径，例如[]string{"成绩"}，为空时插入到第一层
+	Parent []string
+
+	// After
+	// 插入到同一分组中该标题的列之后，为空时追加到分组的最后
+	After string
+
+	// Agg
+	// 汇总行中该列使用的汇总函数，和agg标签一致
+	Agg string
+
+	// Value
+	// 根据一行数据计算列的值
+	Value func(T) any
+}
+
+// AddColumn
+// 注册计算列，多个计算列按注册顺序插入
+// 解析树是共享的，计算列插入到渲染器自己的解析树副本中，渲染时不再使用生成的EncodeRow
+func (r *Renderer[T]) AddColumn(column Column[T]) error {
+	if column.Title == "" {
+		return fmt.Errorf("title of column must not be empty")
+	}
+	if column.Value == nil {
+		return fmt.Errorf("value of column %s must not be nil", column.Title)
+	}
+	if _, ok := aggFunctions[column.Agg]; column.Agg != "" && !ok {
+		return fmt.Errorf("unknown agg %s of column %s", column.Agg, column.Title)
+	}
+
+	columns := append(r.columns[:len(r.columns):len(r.columns)], column)
+	tree, err := r.parser.tree.withColumns(columns)
+	if err != nil {
+		return err
+	}
+
+	r.columns = columns
+	r.tree = tree
+
+	return nil
+}
+
+// withColumns
+// 复制解析树并插入计算列
+func (t *Tree[T]) withColumns(columns []Column[T]) (*Tree[T], error) {
+	cloned := map[*Node]*Node{}
+	nodes := cloneNodes(t.Nodes, nil, cloned)
+	remapFormulas(nodes, cloned)
+
+	for _, column := range columns {
+		if err := insertColumn(&nodes, column); err != nil {
+			return nil, err
+		}
+	}
+
+	tree := &Tree[T]{Nodes: nodes, extra: t.extra}
+	tree.freeze()
+
+	return tree, nil
+}
+
+// cloneNodes
+// 深复制节点，子节点的parent指向复制后的节点，cloned记录原节点和复制后节点的对应关系
+func cloneNodes(nodes []*Node, parent *Node, cloned map[*Node]*Node) []*Node {
+	copies := make([]*Node, len(nodes))
+	for i, node := range nodes {
+		n := *node
+		n.parent = parent
+		n.offsetX = 0
+		n.Children = cloneNodes(node.Children, &n, cloned)
+		copies[i] = &n
+		cloned[node] = &n
+	}
+
+	return copies
+}
+
+// insertColumn
+// 在Parent指定的分组中插入计算列
+func insertColumn[T any](nodes *[]*Node, column Column[T]) error {
+	var parent *Node
+	siblings := nodes
+	for _, title := range column.Parent {
+		var group *Node
+		for _, node := range *siblings {
+			if node.Title == title && len(node.Children) > 0 {
+				group = node
+				break
+			}
+		}
+		if group == nil {
+			return fmt.Errorf("group %s of column %s does not exist", strings.Join(column.Parent, headerSeparator), column.Title)
+		}
+		parent = group
+		siblings = &group.Children
+	}
+
+	pos := len(*siblings)
+	if column.After != "" {
+		pos = -1
+		for i, node := range *siblings {
+			if node.Title == column.After {
+				pos = i + 1
+				break
+			}
+		}
+		if pos < 0 {
+			return fmt.Errorf("column %s does not exist, can not insert column %s after it", column.After, column.Title)
+		}
+	}
+
+	var depth = new(int)
+	var level = 1
+	if parent != nil {
+		level = parent.Level + 1
+	}
+	if len(*siblings) > 0 {
+		depth = (*siblings)[0].Depth
+	}
+
+	value := column.Value
+	node := &Node{
+		parent:   parent,
+		readOnly: true,
+		compute: func(root reflect.Value) any {
+			return value(root.Interface().(T))
+		},
+		Field: column.Title,
+		Title: column.Title,
+		Agg:   column.Agg,
+		Level: level,
+		Depth: depth,
+		Kind:  reflect.Interface,
+	}
+
+	*siblings = append(*siblings, nil)
+	copy((*siblings)[pos+1:], (*siblings)[pos:])
+	(*siblings)[pos] = node
+	for i, n := range *siblings {
+		n.index = i
+	}
+
+	return nil
+}
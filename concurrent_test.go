@@ -0,0 +1,118 @@
+package dynamic
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// concurrentRow
+// 只在并发测试中使用的类型，保证第一次解析发生在多个goroutine中
+type concurrentRow struct {
+	Name  string `xlsx:"col:姓名"`
+	Score struct {
+		Chinese int `xlsx:"col:语文,agg:sum"`
+		Math    int `xlsx:"col:数学,agg:sum"`
+	} `xlsx:"col:成绩"`
+}
+
+func concurrentRows(n int) []concurrentRow {
+	rows := make([]concurrentRow, n)
+	for i := range rows {
+		rows[i].Name = string(rune('A' + i))
+		rows[i].Score.Chinese = 60 + i
+		rows[i].Score.Math = 70 + i
+	}
+
+	return rows
+}
+
+func TestConcurrentRenderAndRead(t *testing.T) {
+	const workers = 8
+
+	// 渲染器和excelize.File不能在多个goroutine中同时写入，每个goroutine使用自己的渲染器和文件
+	// 共享的是解析树缓存、AddColumn复制前的解析树和读取器
+	files := make([]*excelize.File, workers)
+	for i := range files {
+		files[i] = excelize.NewFile()
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*3)
+	start := make(chan struct{})
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+
+			renderer, err := NewRenderer[concurrentRow](files[i], "Sheet1")
+			if err != nil {
+				errs <- err
+				return
+			}
+			err = renderer.AddColumn(Column[concurrentRow]{
+				Title:  "总分",
+				Parent: []string{"成绩"},
+				Agg:    "sum",
+				Value: func(row concurrentRow) any {
+					return row.Score.Chinese + row.Score.Math
+				},
+			})
+			if err != nil {
+				errs <- err
+				return
+			}
+			errs <- renderer.Render(concurrentRows(i + 1))
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	reader, err := NewReader[concurrentRow]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 共享的读取器读取的同时，其他goroutine继续使用同一个解析树渲染
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+
+			grid, err := RenderGrid(concurrentRows(i + 1))
+			if err != nil {
+				errs <- err
+				return
+			}
+			if _, rows := grid.Size(); rows != i+4 {
+				t.Errorf("grid %d: rendered %d rows, want %d", i, rows, i+4)
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+
+			values, err := reader.Read(files[i], "Sheet1")
+			if err != nil {
+				errs <- err
+				return
+			}
+			if len(values) != i+1 {
+				t.Errorf("file %d: read %d rows, want %d", i, len(values), i+1)
+			}
+			if _, err := reader.Inspect(files[i], "Sheet1"); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package dynamic
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CSVOption
+// csv读写配置
+type CSVOption func(*csvOptions)
+
+type csvOptions struct {
+	headerRows bool
+	separator  string
+	comma      rune
+	reader     []ReaderOption
+}
+
+// WithCSVHeaderRows
+// 多级表头按层级写成多行，合并的单元格只在第一列写入标题
+// 默认将多级表头用分隔符连接成一行，例如"书名/主标题/中文名"
+func WithCSVHeaderRows() CSVOption {
+	return func(o *csvOptions) {
+		o.headerRows = true
+	}
+}
+
+// WithCSVSeparator
+// 单行表头中各级标题的分隔符，默认是"/"
+func WithCSVSeparator(separator string) CSVOption {
+	return func(o *csvOptions) {
+		o.separator = separator
+	}
+}
+
+// WithCSVComma
+// csv字段分隔符，默认是逗号
+func WithCSVComma(comma rune) CSVOption {
+	return func(o *csvOptions) {
+		o.comma = comma
+	}
+}
+
+// WithCSVReaderOptions
+// 读取csv时使用的读取器配置，例如表头的匹配方式
+func WithCSVReaderOptions(opts ...ReaderOption) CSVOption {
+	return func(o *csvOptions) {
+		o.reader = append(o.reader, opts...)
+	}
+}
+
+func newCSVOptions(opts []CSVOption) csvOptions {
+	options := csvOptions{separator: headerSeparator, comma: ','}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// MarshalCSV
+// 将数据按照xlsx标签写成csv
+func MarshalCSV[T any](w io.Writer, data []T, opts ...CSVOption) error {
+	options := newCSVOptions(opts)
+
+	parser := Parser[T]{}
+	tree, err := parser.Parse()
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = options.comma
+
+	leaves := tree.Leaves()
+	if options.headerRows {
+		header := make([][]string, tree.MaxLevel())
+		for i := range header {
+			header[i] = make([]string, len(leaves))
+		}
+		for _, meta := range tree.Metas() {
+			header[meta.StartY-1][meta.StartX-1] = meta.Node.Title
+		}
+		if err := writer.WriteAll(header); err != nil {
+			return err
+		}
+	} else {
+		header := make([]string, len(leaves))
+		for i, meta := range leaves {
+			header[i] = strings.Join(titlePaths(meta.Node), options.separator)
+		}
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range data {
+		values := tree.RowValues(row)
+		record := make([]string, len(values))
+		for i, value := range values {
+			record[i] = fmt.Sprint(value)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// UnmarshalCSV
+// 从csv中读取数据，表头的格式需要和MarshalCSV的配置一致
+// 缺失列、默认值、extra字段和转换失败的处理方式和Reader.Read一致
+func UnmarshalCSV[T any](r io.Reader, opts ...CSVOption) ([]T, error) {
+	options := newCSVOptions(opts)
+
+	reader, err := NewReader[T](options.reader...)
+	if err != nil {
+		return nil, err
+	}
+	tree := reader.parser.tree
+
+	csvReader := csv.NewReader(r)
+	csvReader.Comma = options.comma
+	csvReader.FieldsPerRecord = -1
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	headerRows := 1
+	if options.headerRows {
+		headerRows = tree.MaxLevel()
+	}
+	if len(records) < headerRows {
+		return nil, fmt.Errorf("csv has %d rows, but header needs %d rows", len(records), headerRows)
+	}
+
+	var headers [][]string
+	if options.headerRows {
+		headers = csvHeaderPaths(records[:headerRows])
+	} else {
+		for _, cell := range records[0] {
+			var paths []string
+			if cell != "" {
+				paths = strings.Split(cell, options.separator)
+			}
+			headers = append(headers, paths)
+		}
+	}
+
+	var columns = map[*Node]int{}
+	var claimed = map[int]bool{}
+	var missing [][]string
+	for _, meta := range tree.Leaves() {
+		x := reader.findColumn(meta.Node, headers, claimed)
+		if x < 0 {
+			if !meta.Node.IsOptional() {
+				missing = append(missing, titlePaths(meta.Node))
+			}
+			continue
+		}
+		claimed[x] = true
+		columns[meta.Node] = x
+	}
+	if len(missing) > 0 {
+		return nil, &MissingColumnError{Columns: missing}
+	}
+
+	rows := records[headerRows:]
+	var values = make([]T, len(rows))
+	var cellErrors CellErrors
+	for i, record := range rows {
+		for _, meta := range tree.Leaves() {
+			var raw string
+			x, ok := columns[meta.Node]
+			if ok && x < len(record) {
+				raw = record[x]
+			}
+
+			cellValue := reader.cellValue(meta.Node, raw)
+			if cellValue == "" {
+				continue
+			}
+
+			if err := reader.setField(&values[i], meta, cellValue); err != nil {
+				cellError := &CellError{
+					Y:     headerRows + i + 1,
+					Paths: titlePaths(meta.Node),
+					Value: cellValue,
+					Err:   err,
+				}
+				if ok {
+					cellError.X = x + 1
+				}
+				cellErrors = append(cellErrors, cellError)
+			}
+		}
+
+		if tree.extra == "" {
+			continue
+		}
+		for x, paths := range headers {
+			if claimed[x] || len(paths) == 0 {
+				continue
+			}
+			var value string
+			if x < len(record) {
+				value = record[x]
+			}
+			reader.setExtra(&values[i], strings.Join(paths, headerSeparator), value)
+		}
+	}
+
+	if len(cellErrors) > 0 {
+		cellErrors.sort()
+		return values, cellErrors
+	}
+
+	return values, nil
+}
+
+// findColumn
+// 在csv表头中查找和叶子节点路径一致且未被占用的列，不存在时返回-1
+func (r *Reader[T]) findColumn(node *Node, headers [][]string, claimed map[int]bool) int {
+	paths := titlePaths(node)
+
+f:
+	for x, header := range headers {
+		if claimed[x] || len(header) != len(paths) {
+			continue
+		}
+
+		for n, i := node, len(paths)-1; n != nil; n, i = n.parent, i-1 {
+			if !r.matcher.matchTitle(n, header[i]) {
+				continue f
+			}
+		}
+
+		return x
+	}
+
+	return -1
+}
+
+// csvHeaderPaths
+// 从多行表头中还原每一列的标题路径
+// 空单元格表示合并：如果左侧的列和当前列属于同一个父级，那么继承左侧的标题，否则表示上一级标题向下合并
+func csvHeaderPaths(rows [][]string) [][]string {
+	// owner
+	// 每个单元格实际所属的标题单元格所在列，-1表示向下合并或者不存在
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+
+	owner := make([][]int, len(rows))
+	for y, row := range rows {
+		owner[y] = make([]int, width)
+		for x := 0; x < width; x++ {
+			owner[y][x] = -1
+			if x < len(row) && row[x] != "" {
+				owner[y][x] = x
+				continue
+			}
+			if x == 0 || owner[y][x-1] < 0 {
+				continue
+			}
+			// 同一个父级下的空单元格是横向合并
+			if y == 0 || (owner[y-1][x] >= 0 && owner[y-1][x] == owner[y-1][x-1]) {
+				owner[y][x] = owner[y][x-1]
+			}
+		}
+	}
+
+	headers := make([][]string, width)
+	for x := 0; x < width; x++ {
+		for y := range rows {
+			if owner[y][x] >= 0 {
+				headers[x] = append(headers[x], rows[y][owner[y][x]])
+			}
+		}
+	}
+
+	return headers
+}
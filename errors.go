@@ -0,0 +1,119 @@
+package dynamic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MissingColumnError
+// excel中缺少结构体要求的列
+type MissingColumnError struct {
+	Sheet string
+
+	// Columns
+	// 缺少的列，每一列是从上到下的标题路径
+	Columns [][]string
+}
+
+func (e *MissingColumnError) Error() string {
+	columns := make([]string, len(e.Columns))
+	for i, paths := range e.Columns {
+		columns[i] = strings.Join(paths, headerSeparator)
+	}
+
+	if e.Sheet == "" {
+		return fmt.Sprintf("missing required columns: %s", strings.Join(columns, ", "))
+	}
+
+	return fmt.Sprintf("sheet %s is missing required columns: %s", e.Sheet, strings.Join(columns, ", "))
+}
+
+// SheetError
+// 读取某一个sheet时发生的错误
+type SheetError struct {
+	Sheet string
+	Err   error
+}
+
+func (e *SheetError) Error() string {
+	return fmt.Sprintf("sheet %s: %s", e.Sheet, e.Err.Error())
+}
+
+func (e *SheetError) Unwrap() error {
+	return e.Err
+}
+
+// SheetErrors
+// 读取多个sheet时发生的错误，按sheet顺序排列
+type SheetErrors []*SheetError
+
+func (e SheetErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// CellError
+// 单元格的值无法转换为结构体字段的类型
+type CellError struct {
+	// X
+	// 单元格所在列，列不存在时为0
+	X int
+
+	// Y
+	// 单元格所在行
+	Y int
+
+	// Paths
+	// 列的标题路径，从上到下排列
+	Paths []string
+
+	Value string
+	Err   error
+}
+
+// Cell
+// 单元格的excel坐标
+func (e *CellError) Cell() string {
+	if e.X == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s%d", numberToLetters(e.X), e.Y)
+}
+
+func (e *CellError) Error() string {
+	return fmt.Sprintf("cell %s [%s] value [%s]: %s", e.Cell(), strings.Join(e.Paths, headerSeparator), e.Value, e.Err.Error())
+}
+
+func (e *CellError) Unwrap() error {
+	return e.Err
+}
+
+// CellErrors
+// 读取时所有转换失败的单元格，按行列顺序排列
+type CellErrors []*CellError
+
+func (e CellErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// sort
+// 按行列顺序排列
+func (e CellErrors) sort() {
+	sort.Slice(e, func(i, j int) bool {
+		if e[i].Y != e[j].Y {
+			return e[i].Y < e[j].Y
+		}
+		return e[i].X < e[j].X
+	})
+}
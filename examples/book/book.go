@@ -1,3 +1,5 @@
+//go:generate go run github.com/infinitete/dynamic/cmd/dynamicgen -type Book,Student
+
 package main
 
 import (
@@ -55,8 +57,8 @@ type Math struct {
 type Chinese struct {
 	Normal  int `xlsx:"col:平时成绩"`
 	Examing int `xlsx:"col:考试成绩"`
-	Final   int `xlsx:"col:最终成绩"`
-	Point   int `xlsx:"绩点"`
+	Final   int `xlsx:"col:最终成绩,formula:ROUND({Normal}*0.3+{Examing}*0.7,0)"`
+	Point   int `xlsx:"col:绩点,optional"`
 }
 
 type Score struct {
@@ -146,7 +148,11 @@ func books_reader() {
 	}
 	file, _ := excelize.OpenFile("book.xlsx")
 
-	books := reader.Read(file, "书本")
+	books, err := reader.Read(file, "书本")
+	if err != nil {
+		fmt.Printf("Error: %s", err)
+		return
+	}
 	b, _ := json.MarshalIndent(books, "", "  ")
 
 	fmt.Printf("\n%s\n", b)
@@ -173,7 +179,11 @@ func student_reader() {
 		return
 	}
 	file, _ := excelize.OpenFile("book.xlsx")
-	students := reader.Read(file, "Demo")
+	students, err := reader.Read(file, "Demo")
+	if err != nil {
+		fmt.Printf("Error: %s", err)
+		return
+	}
 	b, _ := json.MarshalIndent(students, "", "  ")
 
 	fmt.Printf("\n%s\n", b)
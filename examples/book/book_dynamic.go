@@ -0,0 +1,265 @@
+// Code generated by dynamicgen -type Book,Student; DO NOT EDIT.
+
+package main
+
+import (
+	"strconv"
+
+	"github.com/infinitete/dynamic"
+)
+
+// EncodeRow
+// 按列顺序返回Book一行的值
+func (v Book) EncodeRow() []any {
+	return []any{
+		v.Title.MainTitle.Chinese,
+		v.Title.MainTitle.English,
+		v.Title.MainTitle.Franch,
+		v.Title.SubTitle.Chinese,
+		v.Title.SubTitle.English,
+		v.Title.SubTitle.Franch,
+		v.Author.FirstName,
+		v.Author.LastName,
+		v.Bookmark.Index,
+		v.Bookmark.Page,
+		v.Bookmark.Start.X,
+		v.Bookmark.Start.Y,
+		v.Bookmark.End.X,
+		v.Bookmark.End.Y,
+		v.Remark,
+	}
+}
+
+// DecodeRow
+// 将按列顺序排列的单元格转换为Book
+func (Book) DecodeRow(values []string) (Book, error) {
+	var v Book
+	var errs dynamic.ColumnErrors
+	if len(values) > 0 && values[0] != "" {
+		v.Title.MainTitle.Chinese = values[0]
+	}
+	if len(values) > 1 && values[1] != "" {
+		v.Title.MainTitle.English = values[1]
+	}
+	if len(values) > 2 && values[2] != "" {
+		v.Title.MainTitle.Franch = values[2]
+	}
+	if len(values) > 3 && values[3] != "" {
+		v.Title.SubTitle.Chinese = values[3]
+	}
+	if len(values) > 4 && values[4] != "" {
+		v.Title.SubTitle.English = values[4]
+	}
+	if len(values) > 5 && values[5] != "" {
+		v.Title.SubTitle.Franch = values[5]
+	}
+	if len(values) > 6 && values[6] != "" {
+		v.Author.FirstName = values[6]
+	}
+	if len(values) > 7 && values[7] != "" {
+		v.Author.LastName = values[7]
+	}
+	if len(values) > 8 && values[8] != "" {
+		n, err := strconv.ParseInt(values[8], 10, 0)
+		if err != nil {
+			errs = append(errs, &dynamic.ColumnError{Index: 8, Err: err})
+		} else {
+			v.Bookmark.Index = int(n)
+		}
+	}
+	if len(values) > 9 && values[9] != "" {
+		n, err := strconv.ParseInt(values[9], 10, 0)
+		if err != nil {
+			errs = append(errs, &dynamic.ColumnError{Index: 9, Err: err})
+		} else {
+			v.Bookmark.Page = int(n)
+		}
+	}
+	if len(values) > 10 && values[10] != "" {
+		n, err := strconv.ParseInt(values[10], 10, 0)
+		if err != nil {
+			errs = append(errs, &dynamic.ColumnError{Index: 10, Err: err})
+		} else {
+			v.Bookmark.Start.X = int(n)
+		}
+	}
+	if len(values) > 11 && values[11] != "" {
+		n, err := strconv.ParseInt(values[11], 10, 0)
+		if err != nil {
+			errs = append(errs, &dynamic.ColumnError{Index: 11, Err: err})
+		} else {
+			v.Bookmark.Start.Y = int(n)
+		}
+	}
+	if len(values) > 12 && values[12] != "" {
+		n, err := strconv.ParseInt(values[12], 10, 0)
+		if err != nil {
+			errs = append(errs, &dynamic.ColumnError{Index: 12, Err: err})
+		} else {
+			v.Bookmark.End.X = int(n)
+		}
+	}
+	if len(values) > 13 && values[13] != "" {
+		n, err := strconv.ParseInt(values[13], 10, 0)
+		if err != nil {
+			errs = append(errs, &dynamic.ColumnError{Index: 13, Err: err})
+		} else {
+			v.Bookmark.End.Y = int(n)
+		}
+	}
+	if len(values) > 14 && values[14] != "" {
+		v.Remark = values[14]
+	}
+
+	return v, errs.Err()
+}
+
+// HeaderLayout
+// Book的静态表头布局
+func (Book) HeaderLayout() []dynamic.HeaderCell {
+	return []dynamic.HeaderCell{
+		{Title: "书名", StartX: 1, StartY: 1, EndX: 6, EndY: 1},
+		{Title: "主标题", StartX: 1, StartY: 2, EndX: 3, EndY: 2},
+		{Title: "中文名", StartX: 1, StartY: 3, EndX: 1, EndY: 3},
+		{Title: "英文名", StartX: 2, StartY: 3, EndX: 2, EndY: 3},
+		{Title: "法文名", StartX: 3, StartY: 3, EndX: 3, EndY: 3},
+		{Title: "副标题", StartX: 4, StartY: 2, EndX: 6, EndY: 2},
+		{Title: "中文名", StartX: 4, StartY: 3, EndX: 4, EndY: 3},
+		{Title: "英文名", StartX: 5, StartY: 3, EndX: 5, EndY: 3},
+		{Title: "法文名", StartX: 6, StartY: 3, EndX: 6, EndY: 3},
+		{Title: "作者", StartX: 7, StartY: 1, EndX: 8, EndY: 1},
+		{Title: "姓", StartX: 7, StartY: 2, EndX: 7, EndY: 3},
+		{Title: "名", StartX: 8, StartY: 2, EndX: 8, EndY: 3},
+		{Title: "书签", StartX: 9, StartY: 1, EndX: 14, EndY: 1},
+		{Title: "序号", StartX: 9, StartY: 2, EndX: 9, EndY: 3},
+		{Title: "页数", StartX: 10, StartY: 2, EndX: 10, EndY: 3},
+		{Title: "起始", StartX: 11, StartY: 2, EndX: 12, EndY: 2},
+		{Title: "x", StartX: 11, StartY: 3, EndX: 11, EndY: 3},
+		{Title: "y", StartX: 12, StartY: 3, EndX: 12, EndY: 3},
+		{Title: "终点", StartX: 13, StartY: 2, EndX: 14, EndY: 2},
+		{Title: "x", StartX: 13, StartY: 3, EndX: 13, EndY: 3},
+		{Title: "y", StartX: 14, StartY: 3, EndX: 14, EndY: 3},
+		{Title: "备注", StartX: 15, StartY: 1, EndX: 15, EndY: 3},
+	}
+}
+
+// EncodeRow
+// 按列顺序返回Student一行的值
+func (v Student) EncodeRow() []any {
+	return []any{
+		v.Name,
+		v.Sex,
+		v.Age,
+		v.Score.Chinese.Normal,
+		v.Score.Chinese.Examing,
+		v.Score.Chinese.Final,
+		v.Score.Chinese.Point,
+		v.Score.Math.Normal,
+		v.Score.Math.Examing,
+		v.Score.Math.Final,
+	}
+}
+
+// DecodeRow
+// 将按列顺序排列的单元格转换为Student
+func (Student) DecodeRow(values []string) (Student, error) {
+	var v Student
+	var errs dynamic.ColumnErrors
+	if len(values) > 0 && values[0] != "" {
+		v.Name = values[0]
+	}
+	if len(values) > 1 && values[1] != "" {
+		n, err := strconv.ParseInt(values[1], 10, 0)
+		if err != nil {
+			errs = append(errs, &dynamic.ColumnError{Index: 1, Err: err})
+		} else {
+			v.Sex = int(n)
+		}
+	}
+	if len(values) > 2 && values[2] != "" {
+		n, err := strconv.ParseInt(values[2], 10, 0)
+		if err != nil {
+			errs = append(errs, &dynamic.ColumnError{Index: 2, Err: err})
+		} else {
+			v.Age = int(n)
+		}
+	}
+	if len(values) > 3 && values[3] != "" {
+		n, err := strconv.ParseInt(values[3], 10, 0)
+		if err != nil {
+			errs = append(errs, &dynamic.ColumnError{Index: 3, Err: err})
+		} else {
+			v.Score.Chinese.Normal = int(n)
+		}
+	}
+	if len(values) > 4 && values[4] != "" {
+		n, err := strconv.ParseInt(values[4], 10, 0)
+		if err != nil {
+			errs = append(errs, &dynamic.ColumnError{Index: 4, Err: err})
+		} else {
+			v.Score.Chinese.Examing = int(n)
+		}
+	}
+	if len(values) > 5 && values[5] != "" {
+		n, err := strconv.ParseInt(values[5], 10, 0)
+		if err != nil {
+			errs = append(errs, &dynamic.ColumnError{Index: 5, Err: err})
+		} else {
+			v.Score.Chinese.Final = int(n)
+		}
+	}
+	if len(values) > 6 && values[6] != "" {
+		n, err := strconv.ParseInt(values[6], 10, 0)
+		if err != nil {
+			errs = append(errs, &dynamic.ColumnError{Index: 6, Err: err})
+		} else {
+			v.Score.Chinese.Point = int(n)
+		}
+	}
+	if len(values) > 7 && values[7] != "" {
+		n, err := strconv.ParseInt(values[7], 10, 0)
+		if err != nil {
+			errs = append(errs, &dynamic.ColumnError{Index: 7, Err: err})
+		} else {
+			v.Score.Math.Normal = int(n)
+		}
+	}
+	if len(values) > 8 && values[8] != "" {
+		n, err := strconv.ParseInt(values[8], 10, 0)
+		if err != nil {
+			errs = append(errs, &dynamic.ColumnError{Index: 8, Err: err})
+		} else {
+			v.Score.Math.Examing = int(n)
+		}
+	}
+	if len(values) > 9 && values[9] != "" {
+		n, err := strconv.ParseInt(values[9], 10, 0)
+		if err != nil {
+			errs = append(errs, &dynamic.ColumnError{Index: 9, Err: err})
+		} else {
+			v.Score.Math.Final = int(n)
+		}
+	}
+
+	return v, errs.Err()
+}
+
+// HeaderLayout
+// Student的静态表头布局
+func (Student) HeaderLayout() []dynamic.HeaderCell {
+	return []dynamic.HeaderCell{
+		{Title: "姓名", StartX: 1, StartY: 1, EndX: 1, EndY: 3},
+		{Title: "性别", StartX: 2, StartY: 1, EndX: 2, EndY: 3},
+		{Title: "年龄", StartX: 3, StartY: 1, EndX: 3, EndY: 3},
+		{Title: "成绩", StartX: 4, StartY: 1, EndX: 10, EndY: 1},
+		{Title: "语文", StartX: 4, StartY: 2, EndX: 7, EndY: 2},
+		{Title: "平时成绩", StartX: 4, StartY: 3, EndX: 4, EndY: 3},
+		{Title: "考试成绩", StartX: 5, StartY: 3, EndX: 5, EndY: 3},
+		{Title: "最终成绩", StartX: 6, StartY: 3, EndX: 6, EndY: 3},
+		{Title: "绩点", StartX: 7, StartY: 3, EndX: 7, EndY: 3},
+		{Title: "数学", StartX: 8, StartY: 2, EndX: 10, EndY: 2},
+		{Title: "平时成绩", StartX: 8, StartY: 3, EndX: 8, EndY: 3},
+		{Title: "考试成绩", StartX: 9, StartY: 3, EndX: 9, EndY: 3},
+		{Title: "最终成绩", StartX: 10, StartY: 3, EndX: 10, EndY: 3},
+	}
+}
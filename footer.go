@@ -0,0 +1,164 @@
+package dynamic
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// aggFunctions
+// agg标签对应的SUBTOTAL函数编号，count使用COUNTA，文本列也可以计数
+var aggFunctions = map[string]int{
+	"avg":   1,
+	"count": 3,
+	"max":   4,
+	"min":   5,
+	"sum":   9,
+}
+
+// subtotalPrefix
+// 汇总行单元格公式的前缀，读取时用于识别汇总行
+const subtotalPrefix = "SUBTOTAL("
+
+// WithFooterLabel
+// 汇总行中第一个没有设置agg的列显示的标签，例如"合计"
+func WithFooterLabel(label string) RendererOption {
+	return func(o *rendererOptions) {
+		o.footerLabel = label
+	}
+}
+
+// hasFooter
+// 是否有设置了agg的列
+func (t *Tree[T]) hasFooter() bool {
+	for _, meta := range t.Leaves() {
+		if meta.Node.Agg != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// renderFooter
+// 在最后一行数据下方渲染汇总行，每个设置了agg的列使用SUBTOTAL公式汇总该列的数据
+// written是每个agg列写入的值，汇总结果作为公式缓存的计算结果，不支持公式的后端直接显示该值
+// 没有数据时不渲染汇总行
+func (r *Renderer[T]) renderFooter(rows int, written map[*Node][]any) error {
+	if rows == 0 || !r.tree.hasFooter() {
+		return nil
+	}
+
+	startY := r.tree.MaxLevel() + 1
+	endY := startY + rows - 1
+	y := endY + 1
+
+	label := r.options.footerLabel
+	for _, meta := range r.tree.Leaves() {
+		x := meta.StartX
+		if fn, ok := aggFunctions[meta.Node.Agg]; ok {
+			formula := fmt.Sprintf("%s%d,%s:%s)", subtotalPrefix, fn, cellName(x, startY), cellName(x, endY))
+			if err := r.backend.SetValue(x, y, aggregate(meta.Node.Agg, written[meta.Node])); err != nil {
+				return err
+			}
+			if err := r.backend.SetFormula(x, y, formula); err != nil {
+				return err
+			}
+		} else if label != "" {
+			if err := r.backend.SetValue(x, y, label); err != nil {
+				return err
+			}
+			label = ""
+		}
+
+		if err := r.backend.SetStyle(x, y, x, y, FooterStyle); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// aggregate
+// 计算一列的汇总值，和SUBTOTAL一致，count统计非空单元格，其他函数忽略不是数值的单元格
+// 没有数值时sum为0，其他函数返回nil
+func aggregate(agg string, values []any) any {
+	if agg == "count" {
+		count := 0
+		for _, value := range values {
+			if value != nil && value != "" {
+				count++
+			}
+		}
+		return count
+	}
+
+	var result float64
+	var count int
+	for _, value := range values {
+		number, ok := toFloat(value)
+		if !ok {
+			continue
+		}
+		switch {
+		case count == 0:
+			result = number
+		case agg == "max" && number > result, agg == "min" && number < result:
+			result = number
+		case agg == "sum", agg == "avg":
+			result += number
+		}
+		count++
+	}
+
+	switch {
+	case count == 0 && agg == "sum":
+		return float64(0)
+	case count == 0:
+		return nil
+	case agg == "avg":
+		return result / float64(count)
+	}
+
+	return result
+}
+
+// toFloat
+// 将整数和浮点数类型的值转换为float64
+func toFloat(value any) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+
+	return 0, false
+}
+
+// isFooter
+// 判断第y行是否是渲染器生成的汇总行，表头中任意一列是汇总该列startY+1到y-1行的SUBTOTAL公式时视为汇总行
+// 不依赖解析树，计算列设置的agg生成的汇总行也可以识别
+func (r *Reader[T]) isFooter(sheet *Sheet[T], startY, y int) bool {
+	for _, x := range sheet.HeaderColumns() {
+		formula, err := sheet.file.GetCellFormula(sheet.sheet, cellName(x, y))
+		if err != nil {
+			continue
+		}
+
+		formula = strings.ToUpper(formula)
+		letters := numberToLetters(x)
+		if strings.HasPrefix(formula, subtotalPrefix) && strings.HasSuffix(formula, fmt.Sprintf(",%s%d:%s%d)", letters, startY+1, letters, y-1)) {
+			return true
+		}
+	}
+
+	return false
+}
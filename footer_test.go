@@ -0,0 +1,74 @@
+package dynamic
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type footerItem struct {
+	Name   string `xlsx:"col:名称"`
+	Amount int    `xlsx:"col:金额"`
+}
+
+func TestReadSkipsColumnFooter(t *testing.T) {
+	file := excelize.NewFile()
+	renderer, err := NewRenderer[footerItem](file, "Sheet1", WithFooterLabel("合计"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = renderer.AddColumn(Column[footerItem]{
+		Title: "双倍",
+		Agg:   "sum",
+		Value: func(item footerItem) any { return item.Amount * 2 },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := []footerItem{{"a", 1}, {"b", 2}}
+	if err := renderer.Render(items); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := NewReader[footerItem]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reader.Read(file, "Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, items) {
+		t.Errorf("got %v, want %v", got, items)
+	}
+}
+
+func TestReadKeepsSubtotalDataRow(t *testing.T) {
+	file := excelize.NewFile()
+	renderer, err := NewRenderer[footerItem](file, "Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := []footerItem{{"a", 1}, {"b", 2}}
+	if err := renderer.Render(items); err != nil {
+		t.Fatal(err)
+	}
+	// 不是汇总本列数据的SUBTOTAL公式不视为汇总行
+	if err := file.SetCellFormula("Sheet1", "B3", "SUBTOTAL(9,B1:B1)"); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := NewReader[footerItem]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reader.Read(file, "Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("read %d rows, want 2", len(got))
+	}
+}
@@ -0,0 +1,120 @@
+package dynamic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// formula
+// 解析后的公式，由文本和字段引用交替组成
+// 例如"{Normal}*0.3+{Examing}*0.7"解析为文本["", "*0.3+", "*0.7"]和引用[Normal, Examing]
+type formula struct {
+	texts []string
+	paths [][]string
+	refs  []*Node
+}
+
+// parseFormula
+// 解析公式中用"{}"包围的字段引用，引用是同一个结构体中字段的Go路径，多级用"."分隔
+func parseFormula(s string) (*formula, error) {
+	f := &formula{}
+	s = strings.TrimPrefix(s, "=")
+
+	for {
+		start := strings.Index(s, "{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			return nil, fmt.Errorf("formula %s: unclosed {", s)
+		}
+
+		path := s[start+1 : start+end]
+		if path == "" {
+			return nil, fmt.Errorf("formula %s: empty reference", s)
+		}
+
+		f.texts = append(f.texts, s[:start])
+		f.paths = append(f.paths, strings.Split(path, "."))
+		s = s[start+end+1:]
+	}
+	f.texts = append(f.texts, s)
+
+	return f, nil
+}
+
+// render
+// 生成第y行的公式，引用替换为对应列的单元格
+func (f *formula) render(y int) string {
+	var b strings.Builder
+	row := strconv.Itoa(y)
+	for i, ref := range f.refs {
+		b.WriteString(f.texts[i])
+		b.WriteString(numberToLetters(ref.offsetX))
+		b.WriteString(row)
+	}
+	b.WriteString(f.texts[len(f.texts)-1])
+
+	return b.String()
+}
+
+// resolveFormulas
+// 将所有公式中的引用解析为节点，引用从公式字段所在的结构体开始查找，必须是叶子节点
+func resolveFormulas(nodes []*Node) error {
+	for _, node := range nodes {
+		if err := resolveFormulas(node.Children); err != nil {
+			return err
+		}
+		if node.formula == nil {
+			continue
+		}
+
+		siblings := nodes
+		node.formula.refs = make([]*Node, len(node.formula.paths))
+		for i, paths := range node.formula.paths {
+			ref := findField(siblings, paths)
+			if ref == nil || len(ref.Children) > 0 || ref == node {
+				return fmt.Errorf("formula of field %s: {%s} is not a sibling column", node.Field, strings.Join(paths, "."))
+			}
+			node.formula.refs[i] = ref
+		}
+	}
+
+	return nil
+}
+
+// remapFormulas
+// 复制解析树后，公式中的引用指向复制后的节点
+func remapFormulas(nodes []*Node, cloned map[*Node]*Node) {
+	for _, node := range nodes {
+		remapFormulas(node.Children, cloned)
+		if node.formula == nil {
+			continue
+		}
+
+		f := *node.formula
+		f.refs = make([]*Node, len(node.formula.refs))
+		for i, ref := range node.formula.refs {
+			f.refs[i] = cloned[ref]
+		}
+		node.formula = &f
+	}
+}
+
+// findField
+// 按字段名路径查找节点
+func findField(nodes []*Node, paths []string) *Node {
+	for _, node := range nodes {
+		if node.Field != paths[0] {
+			continue
+		}
+		if len(paths) == 1 {
+			return node
+		}
+		return findField(node.Children, paths[1:])
+	}
+
+	return nil
+}
@@ -2,7 +2,10 @@ module github.com/infinitete/dynamic
 
 go 1.21.5
 
-require github.com/xuri/excelize/v2 v2.8.0
+require (
+	github.com/xuri/excelize/v2 v2.8.0
+	golang.org/x/text v0.12.0
+)
 
 require (
 	github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826 // indirect
@@ -12,5 +15,4 @@ require (
 	github.com/xuri/nfp v0.0.0-20230819163627-dc951e3ffe1a // indirect
 	golang.org/x/crypto v0.12.0 // indirect
 	golang.org/x/net v0.14.0 // indirect
-	golang.org/x/text v0.12.0 // indirect
 )
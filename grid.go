@@ -0,0 +1,335 @@
+package dynamic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/width"
+)
+
+// Area
+// 单元格区域，坐标从1开始
+type Area struct {
+	StartX int
+	StartY int
+	EndX   int
+	EndY   int
+}
+
+// Contains
+// 判断单元格是否在区域内
+func (a Area) Contains(x, y int) bool {
+	return x >= a.StartX && x <= a.EndX && y >= a.StartY && y <= a.EndY
+}
+
+// Grid
+// 内存中的渲染后端，记录单元格的值、公式、合并、样式、列宽和数据验证
+// 可以用于不依赖excel文件的渲染测试
+type Grid struct {
+	values      map[[2]int]any
+	formulas    map[[2]int]string
+	styles      map[[2]int]Style
+	merges      []Area
+	widths      map[int]float64
+	validations map[Area]Validation
+	maxX        int
+	maxY        int
+}
+
+func NewGrid() *Grid {
+	return &Grid{
+		values:      map[[2]int]any{},
+		formulas:    map[[2]int]string{},
+		styles:      map[[2]int]Style{},
+		widths:      map[int]float64{},
+		validations: map[Area]Validation{},
+	}
+}
+
+// RenderGrid
+// 将数据渲染到新的Grid中
+func RenderGrid[T any](data []T, opts ...RendererOption) (*Grid, error) {
+	grid := NewGrid()
+	renderer, err := NewBackendRenderer[T](grid, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := renderer.Render(data); err != nil {
+		return nil, err
+	}
+
+	return grid, nil
+}
+
+func (g *Grid) grow(x, y int) {
+	if x > g.maxX {
+		g.maxX = x
+	}
+	if y > g.maxY {
+		g.maxY = y
+	}
+}
+
+func (g *Grid) MergeCell(startX, startY, endX, endY int) error {
+	area := Area{StartX: startX, StartY: startY, EndX: endX, EndY: endY}
+	for _, merged := range g.merges {
+		// 和excelize一致，重复合并同一区域不会报错
+		if merged == area {
+			return nil
+		}
+		if merged.StartX <= endX && startX <= merged.EndX && merged.StartY <= endY && startY <= merged.EndY {
+			return fmt.Errorf("merge %s:%s overlaps %s:%s", cellName(startX, startY), cellName(endX, endY), cellName(merged.StartX, merged.StartY), cellName(merged.EndX, merged.EndY))
+		}
+	}
+
+	g.merges = append(g.merges, area)
+	g.grow(endX, endY)
+
+	return nil
+}
+
+func (g *Grid) SetValue(x, y int, value any) error {
+	g.values[[2]int{x, y}] = value
+	g.grow(x, y)
+
+	return nil
+}
+
+func (g *Grid) SetFormula(x, y int, formula string) error {
+	g.formulas[[2]int{x, y}] = formula
+	g.grow(x, y)
+
+	return nil
+}
+
+func (g *Grid) SetStyle(startX, startY, endX, endY int, style Style) error {
+	for x := startX; x <= endX; x++ {
+		for y := startY; y <= endY; y++ {
+			g.styles[[2]int{x, y}] = style
+		}
+	}
+	g.grow(endX, endY)
+
+	return nil
+}
+
+func (g *Grid) SetColWidth(startX, endX int, width float64) error {
+	for x := startX; x <= endX; x++ {
+		g.widths[x] = width
+	}
+
+	return nil
+}
+
+func (g *Grid) AddValidation(startX, startY, endX, endY int, validation Validation) error {
+	g.validations[Area{StartX: startX, StartY: startY, EndX: endX, EndY: endY}] = validation
+
+	return nil
+}
+
+func (g *Grid) Clear(startX, startY, endX, endY int) error {
+	area := Area{StartX: startX, StartY: startY, EndX: endX, EndY: endY}
+	for cell := range g.values {
+		if area.Contains(cell[0], cell[1]) {
+			delete(g.values, cell)
+		}
+	}
+	for cell := range g.formulas {
+		if area.Contains(cell[0], cell[1]) {
+			delete(g.formulas, cell)
+		}
+	}
+	for cell := range g.styles {
+		if area.Contains(cell[0], cell[1]) {
+			delete(g.styles, cell)
+		}
+	}
+
+	// 清空后重新计算写入的范围
+	g.maxX, g.maxY = 0, 0
+	for cell := range g.values {
+		g.grow(cell[0], cell[1])
+	}
+	for cell := range g.formulas {
+		g.grow(cell[0], cell[1])
+	}
+	for cell := range g.styles {
+		g.grow(cell[0], cell[1])
+	}
+	for _, merged := range g.merges {
+		g.grow(merged.EndX, merged.EndY)
+	}
+
+	return nil
+}
+
+// Size
+// 已经写入的最大列和最大行
+func (g *Grid) Size() (cols int, rows int) {
+	return g.maxX, g.maxY
+}
+
+// Value
+// 获取单元格的值
+func (g *Grid) Value(x, y int) (any, bool) {
+	value, ok := g.values[[2]int{x, y}]
+	return value, ok
+}
+
+// Formula
+// 获取单元格的公式
+func (g *Grid) Formula(x, y int) (string, bool) {
+	formula, ok := g.formulas[[2]int{x, y}]
+	return formula, ok
+}
+
+// Style
+// 获取单元格的样式
+func (g *Grid) Style(x, y int) (Style, bool) {
+	style, ok := g.styles[[2]int{x, y}]
+	return style, ok
+}
+
+// Merges
+// 获取所有合并区域，按起始行、起始列排序
+func (g *Grid) Merges() []Area {
+	merges := append([]Area{}, g.merges...)
+	sort.Slice(merges, func(i, j int) bool {
+		if merges[i].StartY != merges[j].StartY {
+			return merges[i].StartY < merges[j].StartY
+		}
+		return merges[i].StartX < merges[j].StartX
+	})
+
+	return merges
+}
+
+// ColWidth
+// 获取列宽，未设置时返回false
+func (g *Grid) ColWidth(x int) (float64, bool) {
+	w, ok := g.widths[x]
+	return w, ok
+}
+
+// Validation
+// 获取区域的数据验证
+func (g *Grid) Validation(area Area) (Validation, bool) {
+	validation, ok := g.validations[area]
+	return validation, ok
+}
+
+// mergeOf
+// 获取单元格所在的合并区域
+func (g *Grid) mergeOf(x, y int) (Area, bool) {
+	for _, merged := range g.merges {
+		if merged.Contains(x, y) {
+			return merged, true
+		}
+	}
+
+	return Area{}, false
+}
+
+// text
+// 单元格在文本输出中的内容
+// 被合并的单元格中，和合并起点同一行的用"<"表示，其他的用"^"表示，公式以"="开头
+func (g *Grid) text(x, y int) string {
+	if merged, ok := g.mergeOf(x, y); ok && (merged.StartX != x || merged.StartY != y) {
+		if merged.StartY == y {
+			return "<"
+		}
+		return "^"
+	}
+
+	if formula, ok := g.formulas[[2]int{x, y}]; ok {
+		return "=" + formula
+	}
+
+	return strings.NewReplacer("\r\n", `\n`, "\n", `\n`).Replace(g.valueText(x, y))
+}
+
+// valueText
+// 单元格的值转换为文本，公式单元格返回缓存的计算结果
+func (g *Grid) valueText(x, y int) string {
+	value, ok := g.values[[2]int{x, y}]
+	if !ok || value == nil {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
+
+// rowStyle
+// 一行中第一个设置了样式的单元格的样式，没有设置样式时为BodyStyle
+func (g *Grid) rowStyle(y int) Style {
+	for x := 1; x <= g.maxX; x++ {
+		if style, ok := g.styles[[2]int{x, y}]; ok {
+			return style
+		}
+	}
+
+	return BodyStyle
+}
+
+// String
+// 以文本表格的形式输出，适用于golden文件测试
+// 表头样式的单元格前加"*"
+func (g *Grid) String() string {
+	texts := make([][]string, g.maxY)
+	widths := make([]int, g.maxX)
+	for y := 1; y <= g.maxY; y++ {
+		texts[y-1] = make([]string, g.maxX)
+		for x := 1; x <= g.maxX; x++ {
+			text := g.text(x, y)
+			if style, ok := g.styles[[2]int{x, y}]; ok && style == HeaderStyle && text != "<" && text != "^" {
+				text = "*" + text
+			}
+			texts[y-1][x-1] = text
+			if w := displayWidth(text); w > widths[x-1] {
+				widths[x-1] = w
+			}
+		}
+	}
+
+	var b strings.Builder
+	line := func() {
+		b.WriteString("+")
+		for _, w := range widths {
+			b.WriteString(strings.Repeat("-", w+2))
+			b.WriteString("+")
+		}
+		b.WriteString("\n")
+	}
+
+	line()
+	for _, row := range texts {
+		b.WriteString("|")
+		for x, text := range row {
+			b.WriteString(" ")
+			b.WriteString(text)
+			b.WriteString(strings.Repeat(" ", widths[x]-displayWidth(text)+1))
+			b.WriteString("|")
+		}
+		b.WriteString("\n")
+	}
+	line()
+
+	return b.String()
+}
+
+// displayWidth
+// 文本在等宽字体中的显示宽度，全角字符占两个宽度
+func displayWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			w += 2
+		default:
+			w++
+		}
+	}
+
+	return w
+}
@@ -0,0 +1,144 @@
+package dynamic
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+type gridNames struct {
+	Chinese string `xlsx:"col:中文名"`
+	English string `xlsx:"col:英文名"`
+}
+
+type gridPointer struct {
+	X int `xlsx:"col:x"`
+	Y int `xlsx:"col:y"`
+}
+
+type gridBook struct {
+	NotPresented string `xlsx:"-"`
+	Title        struct {
+		MainTitle gridNames `xlsx:"col:主标题"`
+		SubTitle  gridNames `xlsx:"col:副标题"`
+	} `xlsx:"col:书名"`
+	Author struct {
+		FirstName string `xlsx:"col:姓"`
+		LastName  string `xlsx:"col:名"`
+	} `xlsx:"col:作者"`
+	Bookmark struct {
+		Index int         `xlsx:"col:序号"`
+		Page  int         `xlsx:"col:页数"`
+		Start gridPointer `xlsx:"col:起始"`
+		End   gridPointer `xlsx:"col:终点"`
+	} `xlsx:"col:书签"`
+	Remark string `xlsx:"col:备注"`
+}
+
+type gridCourse struct {
+	Normal  int `xlsx:"col:平时成绩,agg:avg"`
+	Examing int `xlsx:"col:考试成绩,agg:avg"`
+	Final   int `xlsx:"col:最终成绩,agg:max,formula:ROUND({Normal}*0.3+{Examing}*0.7,0)"`
+}
+
+type gridStudent struct {
+	Name  string `xlsx:"col:姓名,agg:count"`
+	Sex   int    `xlsx:"col:性别"`
+	Age   int    `xlsx:"col:年龄"`
+	Score struct {
+		Chinese gridCourse `xlsx:"col:语文"`
+		Math    gridCourse `xlsx:"col:数学"`
+	} `xlsx:"col:成绩"`
+}
+
+func gridBooks() []gridBook {
+	var book gridBook
+	book.NotPresented = "不渲染"
+	book.Title.MainTitle = gridNames{Chinese: "张三之歌", English: "Song of Zhangsan"}
+	book.Title.SubTitle = gridNames{Chinese: "一个法外狂徒的自白", English: "Confession"}
+	book.Author.FirstName = "罗"
+	book.Author.LastName = "用好"
+	book.Bookmark.Index = 1
+	book.Bookmark.Page = 213
+	book.Bookmark.Start = gridPointer{X: 32, Y: 24}
+	book.Bookmark.End = gridPointer{X: 65, Y: 122}
+	book.Remark = "多行\n备注"
+
+	return []gridBook{book}
+}
+
+func gridStudents() []gridStudent {
+	students := make([]gridStudent, 3)
+	for i := range students {
+		s := &students[i]
+		s.Name = []string{"张三", "李四", "王五"}[i]
+		s.Sex = i % 2
+		s.Age = 18 + i
+		s.Score.Chinese = gridCourse{Normal: 80 + i, Examing: 70 + i*5, Final: 73 + i*4}
+		s.Score.Math = gridCourse{Normal: 90 - i, Examing: 60 + i*10, Final: 69 + i*7}
+	}
+
+	return students
+}
+
+// assertGolden
+// 和testdata中的golden文件比较，使用-update参数时重新生成golden文件
+func assertGolden(t *testing.T, name string, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestGridBook(t *testing.T) {
+	grid, err := RenderGrid(gridBooks())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertGolden(t, "grid_book", grid.String())
+}
+
+func TestGridStudent(t *testing.T) {
+	grid, err := RenderGrid(gridStudents(), WithFooterLabel("合计"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertGolden(t, "grid_student", grid.String())
+}
+
+func TestGridStudentRerender(t *testing.T) {
+	grid := NewGrid()
+	renderer, err := NewBackendRenderer[gridStudent](grid, WithFooterLabel("合计"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := renderer.Render(gridStudents()); err != nil {
+		t.Fatal(err)
+	}
+	if err := renderer.Render(gridStudents()[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	assertGolden(t, "grid_student_rerender", grid.String())
+}
@@ -0,0 +1,116 @@
+package dynamic
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+)
+
+// HTMLBackend
+// 输出HTML表格的渲染后端，单元格先写入内存中的Grid，Flush时输出<table>
+// 表头样式的行输出到<thead>，汇总行输出到<tfoot>，合并的单元格使用colspan和rowspan
+// HTML中不计算公式，公式单元格输出缓存的计算结果
+type HTMLBackend struct {
+	*Grid
+	w io.Writer
+}
+
+func NewHTMLBackend(w io.Writer) *HTMLBackend {
+	return &HTMLBackend{Grid: NewGrid(), w: w}
+}
+
+// Flush
+// 将已经写入的单元格输出为<table>
+func (b *HTMLBackend) Flush() error {
+	buf := bufio.NewWriter(b.w)
+
+	var sections = map[Style][]int{}
+	for y := 1; y <= b.maxY; y++ {
+		style := b.rowStyle(y)
+		sections[style] = append(sections[style], y)
+	}
+
+	buf.WriteString("<table>\n")
+	for _, section := range []struct {
+		style Style
+		tag   string
+		cell  string
+	}{
+		{HeaderStyle, "thead", "th"},
+		{BodyStyle, "tbody", "td"},
+		{FooterStyle, "tfoot", "td"},
+	} {
+		rows := sections[section.style]
+		// 没有数据时也输出空的<tbody>
+		if len(rows) == 0 && section.style != BodyStyle {
+			continue
+		}
+
+		fmt.Fprintf(buf, "<%s>\n", section.tag)
+		for _, y := range rows {
+			b.writeRow(buf, y, section.cell)
+		}
+		fmt.Fprintf(buf, "</%s>\n", section.tag)
+	}
+	buf.WriteString("</table>\n")
+
+	return buf.Flush()
+}
+
+// writeRow
+// 输出一行<tr>，被合并的单元格由合并起点的colspan和rowspan覆盖
+func (b *HTMLBackend) writeRow(buf *bufio.Writer, y int, cell string) {
+	buf.WriteString("<tr>")
+	for x := 1; x <= b.maxX; x++ {
+		merged, ok := b.mergeOf(x, y)
+		if ok && (merged.StartX != x || merged.StartY != y) {
+			continue
+		}
+
+		fmt.Fprintf(buf, "<%s", cell)
+		if ok {
+			if cols := merged.EndX - merged.StartX + 1; cols > 1 {
+				fmt.Fprintf(buf, ` colspan="%d"`, cols)
+			}
+			if rows := merged.EndY - merged.StartY + 1; rows > 1 {
+				fmt.Fprintf(buf, ` rowspan="%d"`, rows)
+			}
+		}
+		buf.WriteString(">")
+		buf.WriteString(html.EscapeString(b.valueText(x, y)))
+		fmt.Fprintf(buf, "</%s>", cell)
+	}
+	buf.WriteString("</tr>\n")
+}
+
+// HTMLRenderer
+// 将数据渲染为HTML表格，和Renderer使用相同的选项、计算列和汇总行
+// 每次调用Render都向w输出一个完整的<table>
+type HTMLRenderer[T any] struct {
+	*Renderer[T]
+	backend *HTMLBackend
+}
+
+func NewHTMLRenderer[T any](w io.Writer, opts ...RendererOption) (*HTMLRenderer[T], error) {
+	backend := NewHTMLBackend(w)
+	renderer, err := NewBackendRenderer[T](backend, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HTMLRenderer[T]{
+		Renderer: renderer,
+		backend:  backend,
+	}, nil
+}
+
+// Render
+// 渲染<table>，表头在<thead>中，数据在<tbody>中，汇总行在<tfoot>中
+func (r *HTMLRenderer[T]) Render(data []T) error {
+	if err := r.Renderer.Render(data); err != nil {
+		return err
+	}
+
+	return r.backend.Flush()
+}
@@ -0,0 +1,54 @@
+package dynamic
+
+import (
+	"bytes"
+	"testing"
+)
+
+// tableRenderer
+// HTMLRenderer和MarkdownRenderer共有的方法
+type tableRenderer interface {
+	AddColumn(Column[gridStudent]) error
+	Render([]gridStudent) error
+}
+
+func TestHTMLAndMarkdown(t *testing.T) {
+	opts := []RendererOption{WithFooterLabel("合计"), WithZeroPlaceholder("-")}
+
+	var buf bytes.Buffer
+	htmlRenderer, err := NewHTMLRenderer[gridStudent](&buf, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	markdownRenderer, err := NewMarkdownRenderer[gridStudent](&buf, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		name     string
+		renderer tableRenderer
+	}{
+		{"student_html", htmlRenderer},
+		{"student_markdown", markdownRenderer},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			buf.Reset()
+			err := c.renderer.AddColumn(Column[gridStudent]{
+				Title: "总分",
+				Agg:   "sum",
+				Value: func(s gridStudent) any {
+					return s.Score.Chinese.Final + s.Score.Math.Final
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.renderer.Render(gridStudents()); err != nil {
+				t.Fatal(err)
+			}
+
+			assertGolden(t, c.name, buf.String())
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package dynamic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ContentType
+// xlsx文件的MIME类型
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// DefaultUploadLimit
+// 上传文件的默认大小限制
+const DefaultUploadLimit int64 = 32 << 20
+
+// Handler
+// 导出接口，将load返回的数据渲染为xlsx并作为附件下载
+// filename可以包含中文，会按照RFC 5987编码
+func Handler[T any](filename string, load func(*http.Request) ([]T, error), opts ...SheetOption) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := load(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := Marshal(&buf, data, opts...); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", ContentType)
+		w.Header().Set("Content-Disposition", contentDisposition(filename))
+		w.Header().Set("Content-Length", fmt.Sprint(buf.Len()))
+		w.WriteHeader(http.StatusOK)
+		_, _ = buf.WriteTo(w)
+	})
+}
+
+// contentDisposition
+// 生成附件的Content-Disposition，同时提供ASCII的filename和RFC 5987编码的filename*
+func contentDisposition(filename string) string {
+	var fallback strings.Builder
+	for _, r := range filename {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			fallback.WriteByte('_')
+			continue
+		}
+		fallback.WriteRune(r)
+	}
+
+	return fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`, fallback.String(), encodeRFC5987(filename))
+}
+
+// encodeRFC5987
+// 按照RFC 5987的attr-char对值进行百分号编码
+func encodeRFC5987(value string) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if isAttrChar(c) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0x0f])
+	}
+
+	return b.String()
+}
+
+func isAttrChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+
+	return strings.IndexByte("!#$&+-.^_`|~", c) >= 0
+}
+
+// UploadError
+// 上传文件无法读取，Status是对应的HTTP状态码
+type UploadError struct {
+	Status int
+	Err    error
+}
+
+func (e *UploadError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UploadError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeUpload
+// 从multipart表单的field字段中读取xlsx，文件大小不能超过limit，limit不大于0时使用DefaultUploadLimit
+// 读取结果和错误可能同时返回，参考Reader.Read
+func DecodeUpload[T any](r *http.Request, field string, limit int64, opts ...SheetOption) ([]T, error) {
+	if limit <= 0 {
+		limit = DefaultUploadLimit
+	}
+
+	if r.ContentLength > limit {
+		return nil, &UploadError{Status: http.StatusRequestEntityTooLarge, Err: fmt.Errorf("request body exceeds %d bytes", limit)}
+	}
+
+	r.Body = http.MaxBytesReader(nil, r.Body, limit)
+	if err := r.ParseMultipartForm(limit); err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return nil, &UploadError{Status: http.StatusRequestEntityTooLarge, Err: err}
+		}
+		return nil, &UploadError{Status: http.StatusBadRequest, Err: err}
+	}
+
+	file, header, err := r.FormFile(field)
+	if err != nil {
+		return nil, &UploadError{Status: http.StatusBadRequest, Err: err}
+	}
+	defer file.Close()
+
+	if header.Size > limit {
+		return nil, &UploadError{Status: http.StatusRequestEntityTooLarge, Err: fmt.Errorf("file %s exceeds %d bytes", header.Filename, limit)}
+	}
+
+	return Unmarshal[T](file, opts...)
+}
+
+// ErrorReport
+// 读取失败时返回给调用方的错误报告
+type ErrorReport struct {
+	Message string `json:"message"`
+
+	// Missing
+	// 缺少的列，每一列是从上到下的标题路径
+	Missing [][]string `json:"missing,omitempty"`
+
+	// Cells
+	// 转换失败的单元格
+	Cells []CellReport `json:"cells,omitempty"`
+}
+
+// CellReport
+// 转换失败的单元格
+type CellReport struct {
+	Cell    string   `json:"cell"`
+	Row     int      `json:"row"`
+	Column  []string `json:"column"`
+	Value   string   `json:"value"`
+	Message string   `json:"message"`
+}
+
+// NewErrorReport
+// 将读取时的错误转换为错误报告
+func NewErrorReport(err error) ErrorReport {
+	report := ErrorReport{Message: err.Error()}
+
+	var missing *MissingColumnError
+	if errors.As(err, &missing) {
+		report.Missing = missing.Columns
+	}
+
+	var cellErrors CellErrors
+	if errors.As(err, &cellErrors) {
+		for _, cellError := range cellErrors {
+			report.Cells = append(report.Cells, CellReport{
+				Cell:    cellError.Cell(),
+				Row:     cellError.Y,
+				Column:  cellError.Paths,
+				Value:   cellError.Value,
+				Message: cellError.Err.Error(),
+			})
+		}
+	}
+
+	return report
+}
+
+// UploadHandler
+// 导入接口，从multipart表单的field字段中读取xlsx并交给handle处理
+// 读取失败时以JSON返回ErrorReport，不会调用handle
+func UploadHandler[T any](field string, limit int64, handle func(http.ResponseWriter, *http.Request, []T), opts ...SheetOption) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := DecodeUpload[T](r, field, limit, opts...)
+		if err != nil {
+			status := http.StatusUnprocessableEntity
+			var uploadError *UploadError
+			if errors.As(err, &uploadError) {
+				status = uploadError.Status
+			}
+
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(status)
+			_ = json.NewEncoder(w).Encode(NewErrorReport(err))
+			return
+		}
+
+		handle(w, r, data)
+	})
+}
@@ -0,0 +1,160 @@
+package dynamic
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ColumnIssue
+// 结构体和excel表头之间某一列的差异
+type ColumnIssue struct {
+	// Paths
+	// 列的标题路径，从上到下排列
+	// 对于多余的列，是excel中的表头路径
+	Paths []string
+
+	// Header
+	// 层级不一致时，excel中实际的表头路径
+	Header []string
+
+	// Expected
+	// 按结构体计算的列坐标，多余的列为0
+	Expected int
+
+	// Actual
+	// excel中实际的列坐标，缺失的列为0
+	Actual int
+}
+
+// Report
+// 结构体和excel表头的兼容性报告
+type Report struct {
+	Sheet string
+
+	// Missing
+	// 结构体中存在，但是excel中不存在的列
+	Missing []ColumnIssue
+
+	// Extra
+	// excel中存在，但是结构体中不存在的列
+	Extra []ColumnIssue
+
+	// Misplaced
+	// 标题存在，但是所在的父级分组和结构体不一致的列
+	Misplaced []ColumnIssue
+
+	// Absent
+	// 可选且excel中不存在的列
+	Absent []ColumnIssue
+
+	// Moved
+	// 匹配成功，但是所在的列和结构体计算的列不一致
+	Moved []ColumnIssue
+}
+
+// Compatible
+// 所有列都能匹配时返回true，列的位置和多余的列不影响读取
+func (r Report) Compatible() bool {
+	return len(r.Missing) == 0 && len(r.Misplaced) == 0
+}
+
+// Inspect
+// 对比结构体和excel表头，返回兼容性报告
+func (r *Reader[T]) Inspect(file *excelize.File, sheet string) (*Report, error) {
+	idx, err := file.GetSheetIndex(sheet)
+	if err != nil {
+		return nil, err
+	}
+	if idx == -1 {
+		return nil, fmt.Errorf("sheet %s does not exist", sheet)
+	}
+
+	reader := r.newSheet()
+	if _, err = reader.Read(file, sheet); err != nil {
+		return nil, err
+	}
+
+	report := &Report{Sheet: sheet}
+	matched := r.matchHeaders(reader)
+
+	var claimed = map[int]bool{}
+	for node, x := range matched {
+		if len(node.Children) == 0 {
+			claimed[x] = true
+		}
+	}
+
+	for _, meta := range r.parser.tree.Metas() {
+		if len(meta.Node.Children) > 0 {
+			continue
+		}
+
+		issue := ColumnIssue{
+			Paths:    titlePaths(meta.Node),
+			Expected: meta.StartX,
+		}
+
+		if x, ok := matched[meta.Node]; ok {
+			if x != meta.StartX {
+				issue.Actual = x
+				report.Moved = append(report.Moved, issue)
+			}
+			continue
+		}
+
+		if x := r.findMisplaced(reader, meta.Node, claimed); x > 0 {
+			claimed[x] = true
+			issue.Actual = x
+			issue.Header = reader.HeaderPath(x)
+			report.Misplaced = append(report.Misplaced, issue)
+			continue
+		}
+
+		if meta.Node.IsOptional() {
+			report.Absent = append(report.Absent, issue)
+			continue
+		}
+
+		report.Missing = append(report.Missing, issue)
+	}
+
+	for _, x := range reader.HeaderColumns() {
+		if claimed[x] {
+			continue
+		}
+		report.Extra = append(report.Extra, ColumnIssue{
+			Paths:  reader.HeaderPath(x),
+			Actual: x,
+		})
+	}
+
+	return report, nil
+}
+
+// findMisplaced
+// 在未被占用的表头中查找和叶子节点标题一致的列
+func (r *Reader[T]) findMisplaced(sheet *Sheet[T], node *Node, claimed map[int]bool) int {
+	for _, x := range sheet.HeaderColumns() {
+		if claimed[x] {
+			continue
+		}
+		paths := sheet.HeaderPath(x)
+		if len(paths) > 0 && r.matcher.matchTitle(node, paths[len(paths)-1]) {
+			return x
+		}
+	}
+
+	return 0
+}
+
+// titlePaths
+// 获取节点从上到下的标题路径
+func titlePaths(node *Node) []string {
+	paths := node.Paths()
+	for i, j := 0, len(paths)-1; i < j; i, j = i+1, j-1 {
+		paths[i], paths[j] = paths[j], paths[i]
+	}
+
+	return paths
+}
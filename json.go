@@ -0,0 +1,505 @@
+package dynamic
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Object
+// 保持键顺序的JSON对象，值是字符串、数字或者嵌套的*Object
+// 嵌套的层级和表头的层级一致
+type Object struct {
+	keys   []string
+	values map[string]any
+}
+
+func NewObject() *Object {
+	return &Object{values: map[string]any{}}
+}
+
+// Keys
+// 按插入顺序获取所有键
+func (o *Object) Keys() []string {
+	return o.keys
+}
+
+func (o *Object) Get(key string) (any, bool) {
+	value, ok := o.values[key]
+	return value, ok
+}
+
+// Set
+// 设置键的值，新的键追加在最后
+func (o *Object) Set(key string, value any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// setPath
+// 按照标题路径设置值，中间层级不存在时自动创建
+func (o *Object) setPath(paths []string, value any) {
+	current := o
+	for i, key := range paths {
+		if i == len(paths)-1 {
+			current.Set(key, value)
+			return
+		}
+
+		child, ok := current.values[key].(*Object)
+		if !ok {
+			child = NewObject()
+			current.Set(key, child)
+		}
+		current = child
+	}
+}
+
+// getPath
+// 按照标题路径获取值
+func (o *Object) getPath(paths []string) (any, bool) {
+	current := o
+	for i, key := range paths {
+		value, ok := current.values[key]
+		if !ok {
+			return nil, false
+		}
+		if i == len(paths)-1 {
+			return value, true
+		}
+		if current, ok = value.(*Object); !ok {
+			return nil, false
+		}
+	}
+
+	return nil, false
+}
+
+// leafPaths
+// 获取所有叶子节点的路径，按键的顺序排列
+func (o *Object) leafPaths() [][]string {
+	var paths [][]string
+	for _, key := range o.keys {
+		if child, ok := o.values[key].(*Object); ok {
+			for _, childPaths := range child.leafPaths() {
+				paths = append(paths, append([]string{key}, childPaths...))
+			}
+			continue
+		}
+		paths = append(paths, []string{key})
+	}
+
+	return paths
+}
+
+func (o *Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func (o *Object) UnmarshalJSON(b []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != json.Delim('{') {
+		return fmt.Errorf("json value is not an object")
+	}
+
+	object, err := decodeObject(decoder)
+	if err != nil {
+		return err
+	}
+	*o = *object
+
+	return nil
+}
+
+// decodeObject
+// 按顺序解码对象，左括号已经被读取
+func decodeObject(decoder *json.Decoder) (*Object, error) {
+	object := NewObject()
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return nil, fmt.Errorf("json object key %v is not a string", token)
+		}
+
+		token, err = decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch value := token.(type) {
+		case json.Delim:
+			if value != '{' {
+				return nil, fmt.Errorf("json value of %s must not be an array", key)
+			}
+			child, err := decodeObject(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object.Set(key, child)
+		default:
+			object.Set(key, value)
+		}
+	}
+
+	// 右括号
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// JSONOption
+// JSON转换配置
+type JSONOption func(*jsonOptions)
+
+type jsonOptions struct {
+	ndjson     bool
+	headerRows int
+	reader     []ReaderOption
+	renderer   []RendererOption
+}
+
+// WithNDJSON
+// 使用NDJSON格式，每一行一个对象，默认是JSON数组
+func WithNDJSON() JSONOption {
+	return func(o *jsonOptions) {
+		o.ndjson = true
+	}
+}
+
+// WithJSONHeaderRows
+// 无结构体模式下指定表头的行数，默认根据合并单元格推断
+func WithJSONHeaderRows(rows int) JSONOption {
+	return func(o *jsonOptions) {
+		o.headerRows = rows
+	}
+}
+
+// WithJSONReaderOptions
+// 从sheet读取时使用的读取器配置
+func WithJSONReaderOptions(opts ...ReaderOption) JSONOption {
+	return func(o *jsonOptions) {
+		o.reader = append(o.reader, opts...)
+	}
+}
+
+// WithJSONRendererOptions
+// 写入sheet时使用的渲染器配置
+func WithJSONRendererOptions(opts ...RendererOption) JSONOption {
+	return func(o *jsonOptions) {
+		o.renderer = append(o.renderer, opts...)
+	}
+}
+
+func newJSONOptions(opts []JSONOption) jsonOptions {
+	options := jsonOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// ToJSON
+// 按照结构体读取sheet，并以表头标题为键写成嵌套的JSON对象
+func ToJSON[T any](w io.Writer, file *excelize.File, sheet string, opts ...JSONOption) error {
+	options := newJSONOptions(opts)
+
+	reader, err := NewReader[T](options.reader...)
+	if err != nil {
+		return err
+	}
+	values, err := reader.Read(file, sheet)
+	if err != nil {
+		return err
+	}
+
+	tree := reader.parser.tree
+	leaves := tree.Leaves()
+	objects := make([]*Object, len(values))
+	for i, value := range values {
+		object := NewObject()
+		for n, cell := range tree.RowValues(value) {
+			object.setPath(titlePaths(leaves[n].Node), cell)
+		}
+		objects[i] = object
+	}
+
+	return writeObjects(w, objects, options.ndjson)
+}
+
+// FromJSON
+// 读取以表头标题为键的JSON对象，转换为结构体后渲染到sheet
+func FromJSON[T any](r io.Reader, file *excelize.File, sheet string, opts ...JSONOption) error {
+	options := newJSONOptions(opts)
+
+	objects, err := readObjects(r)
+	if err != nil {
+		return err
+	}
+
+	reader, err := NewReader[T](options.reader...)
+	if err != nil {
+		return err
+	}
+
+	var values = make([]T, len(objects))
+	for i, object := range objects {
+		for _, meta := range reader.parser.tree.Leaves() {
+			value, _ := object.getPath(titlePaths(meta.Node))
+			cellValue := reader.cellValue(meta.Node, jsonString(value))
+			if cellValue == "" {
+				continue
+			}
+			if err := reader.setField(&values[i], meta, cellValue); err != nil {
+				return fmt.Errorf("object %d: %w", i, err)
+			}
+		}
+	}
+
+	renderer, err := NewRenderer[T](file, sheet, options.renderer...)
+	if err != nil {
+		return err
+	}
+	return renderer.Render(values)
+}
+
+// ToJSONSchemaless
+// 不依赖结构体，根据合并的表头单元格推断层级，将sheet写成嵌套的JSON对象
+// 所有的值都是单元格中的字符串
+func ToJSONSchemaless(w io.Writer, file *excelize.File, sheet string, opts ...JSONOption) error {
+	options := newJSONOptions(opts)
+
+	idx, err := file.GetSheetIndex(sheet)
+	if err != nil {
+		return err
+	}
+	if idx == -1 {
+		return fmt.Errorf("sheet %s does not exist", sheet)
+	}
+
+	reader := Sheet[any]{headerLevel: options.headerRows}
+	if _, err := reader.Read(file, sheet); err != nil {
+		return err
+	}
+	if reader.headerLevel <= 0 {
+		reader.headerLevel = reader.inferHeaderLevel()
+	}
+
+	var columns = reader.HeaderColumns()
+	var headers = make([][]string, len(columns))
+	for i, x := range columns {
+		headers[i] = reader.HeaderPath(x)
+	}
+
+	var objects []*Object
+	for y := reader.headerLevel + 1; y <= reader.depth; y++ {
+		object := NewObject()
+		for i, x := range columns {
+			var value string
+			if cellValue, ok := reader.mapdValues[fmt.Sprintf("%s%d", numberToLetters(x), y)]; ok {
+				value = cellValue.Value
+			}
+			object.setPath(headers[i], value)
+		}
+		objects = append(objects, object)
+	}
+
+	return writeObjects(w, objects, options.ndjson)
+}
+
+// FromJSONSchemaless
+// 不依赖结构体，根据JSON对象的嵌套推断表头层级，渲染到sheet
+// 表头按照所有对象中键第一次出现的顺序排列
+// 所有的值都作为字符串写入，WithJSONRendererOptions中的样式和占位符同样生效，缺少或者为null的值视为零值
+func FromJSONSchemaless(r io.Reader, file *excelize.File, sheet string, opts ...JSONOption) error {
+	options := newJSONOptions(opts)
+	rendererOptions := newRendererOptions(options.renderer)
+
+	objects, err := readObjects(r)
+	if err != nil {
+		return err
+	}
+
+	header := NewObject()
+	for _, object := range objects {
+		for _, paths := range object.leafPaths() {
+			if _, ok := header.getPath(paths); !ok {
+				header.setPath(paths, nil)
+			}
+		}
+	}
+
+	depth := 1
+	tree := Tree[*Object]{Nodes: objectNodes(header, nil, &depth, 1)}
+	leaves := tree.Leaves()
+
+	backend := NewExcelizeBackend(file, sheet, rendererOptions.theme)
+	if err := renderHeader(backend, tree.Metas()); err != nil {
+		return err
+	}
+
+	for i, object := range objects {
+		for _, meta := range leaves {
+			value, _ := object.getPath(titlePaths(meta.Node))
+			x, y := meta.StartX, meta.EndY+i+1
+			cell := jsonString(value)
+			if cell == "" && rendererOptions.zeroPlaceholder != nil {
+				cell = *rendererOptions.zeroPlaceholder
+			}
+			if err := backend.SetValue(x, y, cell); err != nil {
+				return err
+			}
+			if err := backend.SetStyle(x, y, x, y, BodyStyle); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// objectNodes
+// 根据对象的键构建解析树节点
+func objectNodes(object *Object, parent *Node, depth *int, level int) []*Node {
+	if level > *depth {
+		*depth = level
+	}
+
+	nodes := make([]*Node, 0, len(object.keys))
+	for i, key := range object.keys {
+		node := &Node{
+			index:  i,
+			parent: parent,
+			Field:  key,
+			Title:  key,
+			Level:  level,
+			Depth:  depth,
+			Kind:   reflect.String,
+		}
+		if child, ok := object.values[key].(*Object); ok {
+			node.Kind = reflect.Struct
+			node.Children = objectNodes(child, node, depth, level+1)
+		}
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
+
+// jsonString
+// 将JSON中的值转换为单元格中的字符串
+func jsonString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// writeObjects
+// 写入JSON数组或者NDJSON
+func writeObjects(w io.Writer, objects []*Object, ndjson bool) error {
+	if !ndjson {
+		if objects == nil {
+			objects = []*Object{}
+		}
+		return json.NewEncoder(w).Encode(objects)
+	}
+
+	encoder := json.NewEncoder(w)
+	for _, object := range objects {
+		if err := encoder.Encode(object); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readObjects
+// 读取JSON数组或者NDJSON，根据第一个非空白字符判断格式
+func readObjects(r io.Reader) ([]*Object, error) {
+	buffered := bufio.NewReader(r)
+	for {
+		b, err := buffered.Peek(1)
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.ContainsRune(" \t\r\n", rune(b[0])) {
+			_, _ = buffered.ReadByte()
+			continue
+		}
+		break
+	}
+
+	decoder := json.NewDecoder(buffered)
+	b, _ := buffered.Peek(1)
+	if b[0] == '[' {
+		var objects []*Object
+		if err := decoder.Decode(&objects); err != nil {
+			return nil, err
+		}
+		return objects, nil
+	}
+
+	var objects []*Object
+	for decoder.More() {
+		var object Object
+		if err := decoder.Decode(&object); err != nil {
+			return nil, err
+		}
+		objects = append(objects, &object)
+	}
+
+	return objects, nil
+}
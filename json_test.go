@@ -0,0 +1,37 @@
+package dynamic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestFromJSONSchemalessPlaceholder(t *testing.T) {
+	input := `[{"姓名":"张三","成绩":{"语文":"90","数学":null}},{"姓名":"李四"}]`
+
+	file := excelize.NewFile()
+	err := FromJSONSchemaless(strings.NewReader(input), file, "Sheet1", WithJSONRendererOptions(WithZeroPlaceholder("-")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := file.GetRows("Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"姓名", "成绩"},
+		{"", "语文", "数学"},
+		{"张三", "90", "-"},
+		{"李四", "-", "-"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d: %v", len(rows), len(want), rows)
+	}
+	for y := range want {
+		if strings.Join(rows[y], ",") != strings.Join(want[y], ",") {
+			t.Errorf("row %d: got %v, want %v", y+1, rows[y], want[y])
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package dynamic
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// MarkdownBackend
+// 输出GitHub Markdown表格的渲染后端，单元格先写入内存中的Grid，Flush时输出表格
+// Markdown不支持合并单元格，多级表头用"/"连接成一行，例如"书名/主标题/中文名"
+// Markdown中不计算公式，公式单元格输出缓存的计算结果
+type MarkdownBackend struct {
+	*Grid
+	w io.Writer
+}
+
+func NewMarkdownBackend(w io.Writer) *MarkdownBackend {
+	return &MarkdownBackend{Grid: NewGrid(), w: w}
+}
+
+// Flush
+// 将已经写入的单元格输出为Markdown表格，表头样式的行合并为一行表头
+func (b *MarkdownBackend) Flush() error {
+	buf := bufio.NewWriter(b.w)
+
+	levels := 0
+	for levels < b.maxY && b.rowStyle(levels+1) == HeaderStyle {
+		levels++
+	}
+
+	header := make([]string, b.maxX)
+	separator := make([]string, b.maxX)
+	for x := 1; x <= b.maxX; x++ {
+		header[x-1] = strings.Join(b.headerPaths(x, levels), headerSeparator)
+		separator[x-1] = "---"
+	}
+	writeMarkdownRow(buf, header)
+	writeMarkdownRow(buf, separator)
+
+	for y := levels + 1; y <= b.maxY; y++ {
+		cells := make([]string, b.maxX)
+		for x := 1; x <= b.maxX; x++ {
+			cells[x-1] = b.valueText(x, y)
+		}
+		writeMarkdownRow(buf, cells)
+	}
+
+	return buf.Flush()
+}
+
+// headerPaths
+// 第x列从上到下的表头标题，同一个合并区域只取一次
+func (b *MarkdownBackend) headerPaths(x, levels int) []string {
+	var paths []string
+	var last [2]int
+	for y := 1; y <= levels; y++ {
+		origin := [2]int{x, y}
+		if merged, ok := b.mergeOf(x, y); ok {
+			origin = [2]int{merged.StartX, merged.StartY}
+		}
+		if origin == last {
+			continue
+		}
+		last = origin
+		paths = append(paths, b.valueText(origin[0], origin[1]))
+	}
+
+	return paths
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+func writeMarkdownRow(w *bufio.Writer, cells []string) {
+	w.WriteString("|")
+	for _, cell := range cells {
+		w.WriteString(" ")
+		w.WriteString(markdownEscaper.Replace(cell))
+		w.WriteString(" |")
+	}
+	w.WriteString("\n")
+}
+
+// MarkdownRenderer
+// 将数据渲染为GitHub Markdown表格，和Renderer使用相同的选项、计算列和汇总行
+// 汇总行作为表格的最后一行输出，每次调用Render都向w输出一个完整的表格
+type MarkdownRenderer[T any] struct {
+	*Renderer[T]
+	backend *MarkdownBackend
+}
+
+func NewMarkdownRenderer[T any](w io.Writer, opts ...RendererOption) (*MarkdownRenderer[T], error) {
+	backend := NewMarkdownBackend(w)
+	renderer, err := NewBackendRenderer[T](backend, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MarkdownRenderer[T]{
+		Renderer: renderer,
+		backend:  backend,
+	}, nil
+}
+
+func (r *MarkdownRenderer[T]) Render(data []T) error {
+	if err := r.Renderer.Render(data); err != nil {
+		return err
+	}
+
+	return r.backend.Flush()
+}
@@ -0,0 +1,61 @@
+package dynamic
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Marshal
+// 将数据渲染为xlsx并写入w，默认使用Sheet1，可以通过WithSheetName指定
+func Marshal[T any](w io.Writer, data []T, opts ...SheetOption) error {
+	options := sheetOptions{name: defaultSheet}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	workbook := NewWorkbook(nil)
+	defer workbook.File().Close()
+
+	if err := Register(workbook, options.name, &data, opts...); err != nil {
+		return err
+	}
+	if err := workbook.Render(); err != nil {
+		return err
+	}
+
+	_, err := workbook.File().WriteTo(w)
+	return err
+}
+
+// Unmarshal
+// 从r中读取xlsx并解析数据，默认读取第一个sheet，可以通过WithSheetName指定
+func Unmarshal[T any](r io.Reader, opts ...SheetOption) ([]T, error) {
+	options := sheetOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sheet := options.name
+	if sheet == "" {
+		sheets := file.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("workbook has no sheet")
+		}
+		sheet = sheets[0]
+	}
+
+	reader, err := NewReader[T](options.reader...)
+	if err != nil {
+		return nil, err
+	}
+
+	return reader.Read(file, sheet)
+}
@@ -0,0 +1,85 @@
+package dynamic
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// Normalizer
+// 表头匹配前对标题进行规范化处理
+type Normalizer func(string) string
+
+// TrimSpace
+// 去除首尾空白
+func TrimSpace(s string) string {
+	return strings.TrimSpace(s)
+}
+
+// FoldCase
+// 大小写折叠，忽略大小写差异
+func FoldCase(s string) string {
+	return cases.Fold().String(s)
+}
+
+// FoldWidth
+// 全角半角折叠，例如"（"和"("视为相同
+func FoldWidth(s string) string {
+	return width.Fold.String(s)
+}
+
+// NFKC
+// Unicode兼容性规范化
+func NFKC(s string) string {
+	return norm.NFKC.String(s)
+}
+
+// DefaultNormalizers
+// 宽松匹配时默认使用的规范化处理
+var DefaultNormalizers = []Normalizer{NFKC, FoldWidth, FoldCase, TrimSpace}
+
+// matcher
+// 表头匹配器
+// 没有规范化处理时为严格匹配，标题必须完全相等
+type matcher struct {
+	normalizers []Normalizer
+}
+
+func (m matcher) normalize(s string) string {
+	for _, fn := range m.normalizers {
+		s = fn(s)
+	}
+
+	return s
+}
+
+// equal
+// 判断两个标题是否一致
+func (m matcher) equal(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if len(m.normalizers) == 0 {
+		return false
+	}
+
+	return m.normalize(a) == m.normalize(b)
+}
+
+// matchTitle
+// 判断节点的标题或者别名是否和表头的值一致
+func (m matcher) matchTitle(node *Node, value string) bool {
+	if m.equal(node.Title, value) {
+		return true
+	}
+
+	for _, alias := range node.Aliases {
+		if m.equal(alias, value) {
+			return true
+		}
+	}
+
+	return false
+}
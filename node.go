@@ -3,21 +3,79 @@ package dynamic
 import (
 	"fmt"
 	"reflect"
-	"strings"
+	"sync"
 )
 
 const ignore = "-"
 
+// extraType
+// 接收未匹配列的字段类型
+var extraType = reflect.TypeOf(map[string]string{})
+
+// leafKinds
+// 可以作为列读写的字段类型，其他类型的字段需要忽略或者使用method
+var leafKinds = map[reflect.Kind]bool{
+	reflect.String:  true,
+	reflect.Bool:    true,
+	reflect.Int:     true,
+	reflect.Int8:    true,
+	reflect.Int16:   true,
+	reflect.Int32:   true,
+	reflect.Int64:   true,
+	reflect.Uint:    true,
+	reflect.Uint8:   true,
+	reflect.Uint16:  true,
+	reflect.Uint32:  true,
+	reflect.Uint64:  true,
+	reflect.Float32: true,
+	reflect.Float64: true,
+}
+
 type Node struct {
 	parent   *Node
 	index    int
 	offsetX  int
 	Field    string
 	Title    string
+	Aliases  []string
+	Optional bool
+	Default  string
 	Level    int
 	Depth    *int
 	Kind     reflect.Kind
 	Children []*Node
+
+	// Method
+	// 计算列的值的方法名
+	Method string
+
+	// Formula
+	// 渲染为excel公式的模板，读取时使用excel中缓存的计算结果
+	Formula string
+
+	// Agg
+	// 汇总行中该列使用的汇总函数
+	Agg string
+
+	// fieldIndex
+	// 字段在根结构体中的索引路径，用于reflect.Value.FieldByIndex
+	fieldIndex []int
+
+	// method
+	// 计算列的方法，接收者是字段所在结构体的指针
+	method reflect.Method
+
+	// readOnly
+	// 只读的列只渲染不读取，包括计算列和未导出的字段
+	readOnly bool
+
+	// compute
+	// 渲染器注册的计算列，根据一行数据计算列的值
+	compute func(root reflect.Value) any
+
+	// formula
+	// 解析后的公式
+	formula *formula
 }
 
 func (node Node) Y() int {
@@ -43,6 +101,32 @@ func (node Node) Cols() int {
 	return cols
 }
 
+// IsOptional
+// 节点是否可以不存在于excel中
+// 节点本身或者任意父节点标记为可选，或者所有子节点都是可选的，只读的节点总是可选的
+func (node Node) IsOptional() bool {
+	if node.readOnly {
+		return true
+	}
+
+	for n := &node; n != nil; n = n.parent {
+		if n.Optional {
+			return true
+		}
+	}
+
+	if len(node.Children) == 0 {
+		return false
+	}
+	for _, child := range node.Children {
+		if !child.IsOptional() {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (node Node) CanMergeRows() bool {
 	return node.Rows() > 1
 }
@@ -82,6 +166,11 @@ func (node Node) Paths() []string {
 	return paths
 }
 
+// schemas
+// 进程内已解析类型的缓存，键为reflect.Type，值为*Tree[T]
+// 解析树放入缓存前已经完成所有计算，之后只读，可以在多个goroutine中共享
+var schemas sync.Map
+
 type Parser[T any] struct {
 	tree *Tree[T]
 }
@@ -103,75 +192,318 @@ func (p *Parser[T]) Parse() (*Tree[T], error) {
 	}
 
 	var t T
-	depth := 1
+	typeOf := reflect.TypeOf(t)
+	if cached, ok := schemas.Load(typeOf); ok {
+		p.tree = cached.(*Tree[T])
+		return p.tree, nil
+	}
 
-	nodes, err := p.parseType(reflect.TypeOf(t), nil, &depth, 1)
+	tree, err := p.parse(typeOf)
 	if err != nil {
 		return nil, err
 	}
 
-	tree := Tree[T]{Nodes: nodes}
-	_ = tree.Metas()
-	p.tree = &tree
+	cached, _ := schemas.LoadOrStore(typeOf, tree)
+	p.tree = cached.(*Tree[T])
 
 	return p.tree, nil
 }
 
+// parse
+// 不经过缓存解析类型，返回已经完成计算的解析树
+func (p *Parser[T]) parse(typeOf reflect.Type) (*Tree[T], error) {
+	depth := 1
+
+	nodes, err := p.parseType(typeOf, nil, &depth, 1)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveFormulas(nodes); err != nil {
+		return nil, err
+	}
+
+	tree := &Tree[T]{Nodes: nodes, extra: extraField(typeOf)}
+	tree.freeze()
+
+	return tree, nil
+}
+
 func (p *Parser[T]) parseType(typeOf reflect.Type, parent *Node, depth *int, level int) ([]*Node, error) {
+	var prefix []int
+	if parent != nil {
+		prefix = parent.fieldIndex
+	}
+
+	fields, err := p.parseFields(typeOf, parent, prefix, depth, level)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := dominantNodes(fields)
+	for i, node := range nodes {
+		node.index = i
+	}
+
+	return nodes, nil
+}
+
+// promotedNode
+// 解析出的字段节点，depth是字段所在的匿名结构体层数，用于处理同名字段
+type promotedNode struct {
+	node   *Node
+	depth  int
+	tagged bool
+}
+
+// parseFields
+// 解析结构体的所有字段
+// 和encoding/json一致，没有col标签的匿名结构体字段会被展开到当前层级，设置了col标签时作为一组嵌套的列
+func (p *Parser[T]) parseFields(typeOf reflect.Type, parent *Node, prefix []int, depth *int, level int) ([]promotedNode, error) {
 	if typeOf.Kind() == reflect.Ptr {
 		typeOf = typeOf.Elem()
 	}
 	fields := typeOf.NumField()
-	nodes := []*Node{}
+	nodes := []promotedNode{}
 
 	*depth = level
 
-	cur := 0
 	for i := 0; i < fields; i++ {
 		field := typeOf.Field(i)
 
+		opts := parseTag(field.Tag.Get(tagName))
+		if opts.ignore {
+			continue
+		}
+
+		if opts.extra {
+			if parent != nil {
+				return nil, fmt.Errorf("extra field %s of type %s must be declared at top level", field.Name, typeOf.Name())
+			}
+			if field.Type != extraType {
+				return nil, fmt.Errorf("extra field %s of type %s must be map[string]string", field.Name, typeOf.Name())
+			}
+			if !field.IsExported() {
+				return nil, fmt.Errorf("extra field %s of type %s must be exported", field.Name, typeOf.Name())
+			}
+			continue
+		}
+
+		// 和encoding/json一致，未导出的匿名结构体仍然展开其中导出的字段
+		embedded := field.Anonymous && field.Type.Kind() == reflect.Struct
+		if !field.IsExported() && !embedded && !opts.include && opts.method == "" {
+			continue
+		}
+
 		if field.Type.Kind() == reflect.Ptr {
 			return nil, fmt.Errorf("type %s mus not be contains  pointer field", typeOf.Name())
 		}
 
-		tag := field.Tag.Get("xlsx")
-		if tag == ignore {
+		index := fieldIndex(prefix, i)
+
+		if embedded && opts.col == "" {
+			promoted, err := p.parseFields(field.Type, parent, index, depth, level)
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range promoted {
+				f.depth++
+				f.node.Optional = f.node.Optional || opts.optional
+				nodes = append(nodes, f)
+			}
 			continue
 		}
+
 		node := &Node{
-			index:  cur,
-			parent: parent,
-			Level:  level,
-			Depth:  depth,
+			parent:     parent,
+			fieldIndex: index,
+			readOnly:   (parent != nil && parent.readOnly) || (!field.IsExported() && !field.Anonymous),
+			Level:      level,
+			Depth:      depth,
 		}
-		cur++
 		node.Field = field.Name
-		cases := strings.Split(tag, ",")
-		for _, c := range cases {
-			kv := strings.Split(c, ":")
-			if kv[0] == "col" {
-				if len(kv) == 2 {
-					node.Title = kv[1]
-				}
-				break // get col only
-			}
-		}
+		node.Title = opts.col
+		node.Aliases = opts.alias
+		node.Optional = opts.optional
+		node.Default = opts.defaultValue
 		if node.Title == "" {
 			node.Title = node.Field
 		}
 
 		node.Kind = field.Type.Kind()
 
-		if node.Kind == reflect.Struct {
+		if opts.formula != "" {
+			if node.Kind == reflect.Struct && opts.method == "" {
+				return nil, fmt.Errorf("field %s of type %s: formula can only be used on a column", field.Name, typeOf.Name())
+			}
+			f, err := parseFormula(opts.formula)
+			if err != nil {
+				return nil, fmt.Errorf("field %s of type %s: %w", field.Name, typeOf.Name(), err)
+			}
+			node.Formula = opts.formula
+			node.formula = f
+		}
+
+		if opts.agg != "" {
+			if _, ok := aggFunctions[opts.agg]; !ok {
+				return nil, fmt.Errorf("field %s of type %s: unknown agg %s", field.Name, typeOf.Name(), opts.agg)
+			}
+			if node.Kind == reflect.Struct && opts.method == "" {
+				return nil, fmt.Errorf("field %s of type %s: agg can only be used on a column", field.Name, typeOf.Name())
+			}
+			node.Agg = opts.agg
+		}
+
+		if opts.method != "" {
+			if err := node.bindMethod(typeOf, opts.method); err != nil {
+				return nil, err
+			}
+		} else if node.Kind == reflect.Struct {
 			children, err := p.parseType(field.Type, node, depth, level+1)
 			if err != nil {
 				return nil, err
 			}
 			node.Children = children
+			if len(children) == 0 {
+				return nil, fmt.Errorf("field %s of type %s has no columns", field.Name, typeOf.Name())
+			}
+		} else if !leafKinds[node.Kind] {
+			return nil, fmt.Errorf("field %s of type %s: unsupported kind %s", field.Name, typeOf.Name(), node.Kind)
 		}
 
-		nodes = append(nodes, node)
+		nodes = append(nodes, promotedNode{node: node, tagged: opts.col != ""})
 	}
 
 	return nodes, nil
 }
+
+// bindMethod
+// 绑定计算列的方法，方法定义在字段所在的结构体上，没有参数并且只有一个返回值
+func (node *Node) bindMethod(owner reflect.Type, name string) error {
+	method, ok := reflect.PointerTo(owner).MethodByName(name)
+	if !ok {
+		return fmt.Errorf("method %s of field %s is not defined on type %s", name, node.Field, owner.Name())
+	}
+	if method.Type.NumIn() != 1 || method.Type.NumOut() != 1 {
+		return fmt.Errorf("method %s of type %s must have no arguments and return one value", name, owner.Name())
+	}
+
+	node.Method = name
+	node.method = method
+	node.readOnly = true
+	node.Kind = method.Type.Out(0).Kind()
+
+	return nil
+}
+
+// value
+// 获取叶子节点在结构体中的值，root必须是可寻址的
+// 计算列调用所在结构体的方法，未导出的字段按类型读取
+func (node *Node) value(root reflect.Value) any {
+	if node.compute != nil {
+		return node.compute(root)
+	}
+
+	if node.method.Func.IsValid() {
+		owner := root.FieldByIndex(node.fieldIndex[:len(node.fieldIndex)-1])
+		return node.method.Func.Call([]reflect.Value{owner.Addr()})[0].Interface()
+	}
+
+	field := root.FieldByIndex(node.fieldIndex)
+	if field.CanInterface() {
+		return field.Interface()
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return field.Uint()
+	case reflect.Float32, reflect.Float64:
+		return field.Float()
+	case reflect.Bool:
+		return field.Bool()
+	}
+
+	return field.String()
+}
+
+// dominantNodes
+// 按encoding/json的规则处理同名字段：
+// 1. 匿名结构体层数最少的字段优先
+// 2. 层数相同时，只有一个字段设置了col标签，那么该字段优先
+// 3. 否则同名的字段都被忽略
+func dominantNodes(fields []promotedNode) []*Node {
+	var groups = map[string][]promotedNode{}
+	for _, f := range fields {
+		groups[f.node.Field] = append(groups[f.node.Field], f)
+	}
+
+	var dominant = map[*Node]bool{}
+	for _, group := range groups {
+		var candidates []promotedNode
+		for _, f := range group {
+			if len(candidates) == 0 || f.depth < candidates[0].depth {
+				candidates = []promotedNode{f}
+			} else if f.depth == candidates[0].depth {
+				candidates = append(candidates, f)
+			}
+		}
+
+		if len(candidates) == 1 {
+			dominant[candidates[0].node] = true
+			continue
+		}
+
+		var tagged []promotedNode
+		for _, f := range candidates {
+			if f.tagged {
+				tagged = append(tagged, f)
+			}
+		}
+		if len(tagged) == 1 {
+			dominant[tagged[0].node] = true
+		}
+	}
+
+	nodes := []*Node{}
+	for _, f := range fields {
+		if dominant[f.node] {
+			nodes = append(nodes, f.node)
+		}
+	}
+
+	return nodes
+}
+
+// fieldIndex
+// 获取字段在根结构体中的索引路径
+func fieldIndex(prefix []int, i int) []int {
+	index := make([]int, len(prefix), len(prefix)+1)
+	copy(index, prefix)
+
+	return append(index, i)
+}
+
+// extraField
+// 获取类型中用于接收未匹配列的字段名，不存在时返回空字符串
+func extraField(typeOf reflect.Type) string {
+	if typeOf.Kind() == reflect.Ptr {
+		typeOf = typeOf.Elem()
+	}
+
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		opts := parseTag(field.Tag.Get(tagName))
+		if opts.extra {
+			return field.Name
+		}
+		// 展开的匿名结构体中的extra字段
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && !opts.ignore && opts.col == "" {
+			if name := extraField(field.Type); name != "" {
+				return name
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,66 @@
+package dynamic
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+type unexportedExtra struct {
+	Name  string            `xlsx:"col:名称"`
+	extra map[string]string `xlsx:",extra"`
+}
+
+type unexportedPointer struct {
+	Name string `xlsx:"col:名称"`
+	mu   *sync.Mutex
+}
+
+type exportedPointer struct {
+	Name  string `xlsx:"col:名称"`
+	Mutex *sync.Mutex
+}
+
+type groupFormula struct {
+	Score struct {
+		A int
+		B int
+	} `xlsx:"col:成绩,formula:{A}+{B}"`
+}
+
+// parseError
+// 解析T，返回错误信息
+func parseError[T any]() string {
+	_, err := (&Parser[T]{}).Parse()
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		err  string
+		want string
+	}{
+		{"unexported extra", parseError[unexportedExtra](), "extra field extra of type unexportedExtra must be exported"},
+		{"exported pointer", parseError[exportedPointer](), "pointer field"},
+		{"group formula", parseError[groupFormula](), "field Score of type groupFormula: formula can only be used on a column"},
+	} {
+		if !strings.Contains(c.err, c.want) {
+			t.Errorf("%s: got error %q, want %q", c.name, c.err, c.want)
+		}
+	}
+}
+
+func TestParseSkipsUnexportedPointer(t *testing.T) {
+	tree, err := (&Parser[unexportedPointer]{}).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leaves := len(tree.Leaves()); leaves != 1 {
+		t.Errorf("parsed %d leaves, want 1", leaves)
+	}
+}
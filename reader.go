@@ -2,7 +2,6 @@ package dynamic
 
 import (
 	"fmt"
-	"log"
 	"reflect"
 	"strconv"
 	"strings"
@@ -10,108 +9,331 @@ import (
 	"github.com/xuri/excelize/v2"
 )
 
+// headerSeparator
+// 表头路径的分隔符
+const headerSeparator = "/"
+
 // Reader
 // 实现从excel中读取数据到指定结构体，并返回结构体数组
+// 读取器不保存读取状态，一个读取器可以在多个goroutine中同时读取不同的文件
 type Reader[T any] struct {
 
 	// parser
 	// 结构体解析器
 	parser *Parser[T]
 
-	// sheet
-	// excel读取器
-	sheet *Sheet[T]
+	// matcher
+	// 表头匹配器
+	matcher matcher
+
+	// placeholder
+	// 和占位符相等的单元格视为空
+	placeholder string
 }
 
-func NewReader[T any]() (*Reader[T], error) {
-	parser := Parser[T]{}
-	tree, err := parser.Parse()
-	if err != nil {
-		return nil, err
+// ReaderOption
+// 读取器配置
+type ReaderOption func(*readerOptions)
+
+type readerOptions struct {
+	normalizers []Normalizer
+	placeholder string
+}
+
+// WithEmptyPlaceholder
+// 读取时和占位符相等的单元格视为空，和渲染器的WithZeroPlaceholder对应
+func WithEmptyPlaceholder(placeholder string) ReaderOption {
+	return func(o *readerOptions) {
+		o.placeholder = placeholder
 	}
+}
 
-	sheet := Sheet[T]{
-		headerLevel: tree.MaxLevel(),
+// WithStrictMatch
+// 严格匹配表头，标题(或别名)必须和excel中的值完全相等，这是默认的匹配方式
+func WithStrictMatch() ReaderOption {
+	return func(o *readerOptions) {
+		o.normalizers = nil
+	}
+}
+
+// WithNormalizedMatch
+// 规范化后匹配表头，未指定规范化处理时使用DefaultNormalizers
+func WithNormalizedMatch(normalizers ...Normalizer) ReaderOption {
+	return func(o *readerOptions) {
+		if len(normalizers) == 0 {
+			normalizers = DefaultNormalizers
+		}
+		o.normalizers = normalizers
+	}
+}
+
+func NewReader[T any](opts ...ReaderOption) (*Reader[T], error) {
+	options := readerOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	parser := Parser[T]{}
+	if _, err := parser.Parse(); err != nil {
+		return nil, err
 	}
 
 	return &Reader[T]{
-		parser: &parser,
-		sheet:  &sheet,
+		parser:      &parser,
+		matcher:     matcher{normalizers: options.normalizers},
+		placeholder: options.placeholder,
 	}, nil
 }
 
 // Read
 // 从Excel读取并解析数据到对应结构体
-func (r *Reader[T]) Read(file *excelize.File, sheet string) []T {
+// 只读取和表头匹配成功的列，结构体中必需的列在excel中不存在时返回MissingColumnError
+// 可选的列不存在或者单元格为空时使用default标签指定的值，没有默认值时保留零值
+// 单元格转换失败时该字段保留零值，读取结果和CellErrors同时返回
+func (r *Reader[T]) Read(file *excelize.File, sheet string) ([]T, error) {
 	idx, err := file.GetSheetIndex(sheet)
+	if err != nil {
+		return nil, err
+	}
 	if idx == -1 {
-		return nil
+		return nil, fmt.Errorf("sheet %s does not exist", sheet)
 	}
 
-	_, err = r.sheet.Read(file, sheet)
-	if err != nil {
-		return nil
+	reader := r.newSheet()
+	if _, err = reader.Read(file, sheet); err != nil {
+		return nil, err
 	}
 
-	// 向下匹配法
-	// 判断结构体节点是否和excel节点匹配的步骤
-	// 1. 判断当前节点的名称和excel节点的值是否一致
-	// 2. 如果有子节点，那么逐个判断子节点的值和excel对应子节点的值是否一致
-	// 3. 如果子节点存在子节点，那么重复2-3步骤，直至完成匹配
-	// 4. 如果有节点不匹配，那么跳出匹配
-	// 5. 完成匹配
-	headers := r.sheet.GetHeaderCellValues()
-	for _, header := range headers {
-		if header.Alias != nil {
+	var claimed = map[int]bool{}
+	var columns = map[*Node]int{}
+	for node, x := range r.matchHeaders(reader) {
+		if len(node.Children) == 0 {
+			claimed[x] = true
+			columns[node] = x
+		}
+	}
+
+	var missing [][]string
+	for _, meta := range r.parser.tree.Metas() {
+		if len(meta.Node.Children) > 0 {
 			continue
 		}
+		if _, ok := columns[meta.Node]; !ok && !meta.Node.IsOptional() {
+			missing = append(missing, titlePaths(meta.Node))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &MissingColumnError{Sheet: sheet, Columns: missing}
+	}
 
-		nodes := r.parser.tree.FindNodesByTag(header.Y, header.Value)
+	var startY = r.parser.tree.maxLevel
+	var depth = reader.depth
+	if depth < startY {
+		depth = startY
+	}
+	// 跳过渲染器生成的汇总行
+	if depth > startY && r.isFooter(reader, startY, depth) {
+		depth--
+	}
 
-	f:
-		for i := 0; i < len(nodes); i++ {
-			node := nodes[i]
-			if node.Title == header.Value && r.fullMatch(node, header) {
-				node.offsetX = header.X
-				break f
-			}
+	// 每一列的字母只计算一次，未匹配的列为空
+	var leaves = r.parser.tree.Leaves()
+	var letters = make([]string, len(leaves))
+	for i, meta := range leaves {
+		if x, ok := columns[meta.Node]; ok {
+			letters[i] = numberToLetters(x)
 		}
 	}
 
-	var nodeValues = map[*Node]map[int]string{}
+	var values = make([]T, depth-startY)
+	var cellErrors CellErrors
+	var decoder = r.parser.tree.generated().decoder
+	var row = make([]string, len(leaves))
+	for cur := range values {
+		y := startY + cur + 1
+		for i, meta := range leaves {
+			var value string
+			if letters[i] != "" {
+				if cellValue, ok := reader.mapdValues[letters[i]+strconv.Itoa(y)]; ok {
+					value = cellValue.Value
+				}
+			}
+			row[i] = r.cellValue(meta.Node, value)
+		}
 
-	for _, meta := range r.parser.tree.metas {
-		if len(meta.Node.Children) > 0 {
+		if decoder != nil {
+			cellErrors = append(cellErrors, r.decodeRow(decoder, &values[cur], row, columns, y)...)
 			continue
 		}
-		nodeValues[meta.Node] = make(map[int]string)
+
+		for i, meta := range leaves {
+			// 空单元格保留零值
+			if row[i] == "" {
+				continue
+			}
+
+			if err := r.setField(&values[cur], meta, row[i]); err != nil {
+				cellErrors = append(cellErrors, &CellError{
+					X:     columns[meta.Node],
+					Y:     y,
+					Paths: titlePaths(meta.Node),
+					Value: row[i],
+					Err:   err,
+				})
+			}
+		}
+	}
+
+	if r.parser.tree.extra != "" {
+		r.readExtra(reader, values, claimed)
+	}
+
+	if len(cellErrors) > 0 {
+		cellErrors.sort()
+		return values, cellErrors
+	}
+
+	return values, nil
+}
+
+// ReadAll
+// 读取工作簿中所有sheet，返回以sheet名称为键的结果
+// 表头不匹配的sheet不会出现在结果中，而是通过SheetErrors返回
+// 部分sheet失败或者存在转换失败的单元格时，结果和错误同时返回
+func (r *Reader[T]) ReadAll(file *excelize.File) (map[string][]T, error) {
+	var results = map[string][]T{}
+	var errs SheetErrors
+
+	for _, sheet := range file.GetSheetList() {
+		values, err := r.Read(file, sheet)
+		if err != nil {
+			errs = append(errs, &SheetError{Sheet: sheet, Err: err})
+		}
+		if values != nil {
+			results[sheet] = values
+		}
+	}
+
+	if len(errs) > 0 {
+		return results, errs
+	}
+
+	return results, nil
+}
+
+// newSheet
+// 每次读取使用新的Sheet，读取器本身不保存任何读取状态，可以在多个goroutine中共享
+func (r *Reader[T]) newSheet() *Sheet[T] {
+	return &Sheet[T]{
+		headerLevel: r.parser.tree.MaxLevel(),
+	}
+}
+
+// decodeRow
+// 使用生成的DecodeRow将按叶子节点顺序排列的一行转换为结构体
+func (r *Reader[T]) decodeRow(decoder RowDecoder[T], t *T, row []string, columns map[*Node]int, y int) (cellErrors CellErrors) {
+	value, err := decoder.DecodeRow(row)
+	*t = value
+	if err == nil {
+		return nil
+	}
+
+	columnErrors, ok := err.(ColumnErrors)
+	if !ok {
+		return CellErrors{&CellError{Y: y, Err: err}}
 	}
 
+	leaves := r.parser.tree.Leaves()
+	for _, columnError := range columnErrors {
+		node := leaves[columnError.Index].Node
+		cellErrors = append(cellErrors, &CellError{
+			X:     columns[node],
+			Y:     y,
+			Paths: titlePaths(node),
+			Value: row[columnError.Index],
+			Err:   columnError.Err,
+		})
+	}
+
+	return cellErrors
+}
+
+// readExtra
+// 将所有未匹配的表头列写入extra字段，键为以"/"连接的表头路径
+func (r *Reader[T]) readExtra(sheet *Sheet[T], values []T, claimed map[int]bool) {
 	var startY = r.parser.tree.maxLevel
-	var depth = r.sheet.depth
-	for node, values := range nodeValues {
-		for i := startY + 1; i <= depth; i++ {
-			cell := fmt.Sprintf("%s%d", numberToLetters(node.offsetX), i)
-			cellValue, ok := r.sheet.mapdValues[cell]
-			if !ok {
-				continue
+	for _, x := range sheet.HeaderColumns() {
+		if claimed[x] {
+			continue
+		}
+
+		key := strings.Join(sheet.HeaderPath(x), headerSeparator)
+		letters := numberToLetters(x)
+		for i := range values {
+			var value string
+			if cellValue, ok := sheet.mapdValues[letters+strconv.Itoa(startY+i+1)]; ok {
+				value = cellValue.Value
 			}
-			values[i] = cellValue.Value
+
+			r.setExtra(&values[i], key, value)
 		}
 	}
-	var values = make([]T, depth-startY)
-	for node, value := range nodeValues {
-		for i := startY + 1; i <= depth; i++ {
-			var t = values[i-startY-1]
-			err := r.setStructValue(&t, node.Kind, r.parser.tree.paths(node), value[i])
-			if err != nil {
-				log.Printf("Error: %s", err.Error())
+}
+
+// setExtra
+// 将未匹配列的值写入extra字段
+func (r *Reader[T]) setExtra(t *T, key string, value string) {
+	field := reflect.ValueOf(t).Elem().FieldByName(r.parser.tree.extra)
+	if field.IsNil() {
+		field.Set(reflect.MakeMap(extraType))
+	}
+	field.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(value))
+}
+
+// cellValue
+// 获取单元格实际写入结构体的值
+// 和占位符相等的单元格视为空，空单元格使用节点的默认值
+func (r *Reader[T]) cellValue(node *Node, value string) string {
+	if r.placeholder != "" && value == r.placeholder {
+		value = ""
+	}
+	if value == "" {
+		value = node.Default
+	}
+
+	return value
+}
+
+// matchHeaders
+// 匹配结构体节点和excel表头，返回匹配成功的节点及其所在列
+func (r *Reader[T]) matchHeaders(sheet *Sheet[T]) map[*Node]int {
+	// 向下匹配法
+	// 判断结构体节点是否和excel节点匹配的步骤
+	// 1. 判断当前节点的名称和excel节点的值是否一致
+	// 2. 如果有子节点，那么逐个判断子节点的值和excel对应子节点的值是否一致
+	// 3. 如果子节点存在子节点，那么重复2-3步骤，直至完成匹配
+	// 4. 如果有节点不匹配，那么跳出匹配
+	// 5. 完成匹配
+	var matched = map[*Node]int{}
+	headers := sheet.GetHeaderCellValues()
+	for _, header := range headers {
+		if header.Alias != nil {
+			continue
+		}
+
+		nodes := r.findNodes(header.Y, header.Value)
+		for _, node := range nodes {
+			if _, ok := matched[node]; ok {
+				continue
+			}
+			if r.fullMatch(sheet, node, header) {
+				matched[node] = header.X
+				break
 			}
-			values[i-startY-1] = t
 		}
 	}
 
-	return values
+	return matched
 }
 
 // match
@@ -128,15 +350,54 @@ func (r *Reader[T]) match(node *Node, cell *CellValue) bool {
 		return false
 	}
 
-	return node.Title == cell.Value
+	return r.matcher.matchTitle(node, cell.Value)
+}
+
+// findNodes
+// 获取某一层级中和表头值匹配的所有节点
+func (r *Reader[T]) findNodes(level int, value string) (res []*Node) {
+	res = make([]*Node, 0)
+	for _, meta := range r.parser.tree.Metas() {
+		if meta.Node.Level == level && r.matcher.matchTitle(meta.Node, value) {
+			res = append(res, meta.Node)
+		}
+	}
+
+	return
+}
+
+// findChild
+// 获取和表头值匹配的子节点
+func (r *Reader[T]) findChild(node *Node, value string) *Node {
+	for _, child := range node.Children {
+		if r.matcher.matchTitle(child, value) {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// matchPaths
+// 向上逐级判断节点和表头的路径是否一致
+func (r *Reader[T]) matchPaths(node *Node, cell *CellValue) bool {
+	for node != nil && cell != nil {
+		if !r.matcher.matchTitle(node, cell.Value) {
+			return false
+		}
+		node = node.parent
+		cell = cell.Parent
+	}
+
+	return node == nil && cell == nil
 }
 
 // fullMatch
 // 判断节点和excel的节点是否全匹配
 // 全匹配的条件：
-// 1. 当前节点匹配,层级、值一致、子节点数量一致
-// 2. 所有子节点匹配
-func (r *Reader[T]) fullMatch(node *Node, cell *CellValue) bool {
+// 1. 当前节点匹配,层级、值一致
+// 2. 所有子节点匹配，除可选节点外的子节点都存在
+func (r *Reader[T]) fullMatch(sheet *Sheet[T], node *Node, cell *CellValue) bool {
 	if node == nil || cell == nil {
 		return false
 	}
@@ -145,88 +406,71 @@ func (r *Reader[T]) fullMatch(node *Node, cell *CellValue) bool {
 		return false
 	}
 
-	if node.Title != cell.Value {
+	if !r.matcher.matchTitle(node, cell.Value) {
 		return false
 	}
 
 	// 向上匹配
 	if node.parent != nil && cell.Parent != nil {
-		if strings.Join(node.Paths(), ".") != strings.Join(cell.ValuePaths(), ".") {
+		if !r.matchPaths(node, cell) {
 			return false
 		}
 	}
 
 	// 子节点完全匹配
-	children := r.sheet.GetChildrenCellValues(cell)
-	if len(children) != len(node.Children) {
+	// 可选的子节点可以不存在于excel中
+	children := sheet.GetChildrenCellValues(cell)
+	if len(children) > len(node.Children) {
 		return false
 	}
 
+	var present = map[*Node]bool{}
 	for _, child := range children {
-		nodeChild := node.FindChildByTitle(child.Value)
+		nodeChild := r.findChild(node, child.Value)
 		if nodeChild == nil {
 			return false
 		}
-		if !r.fullMatch(nodeChild, child) {
+		if !r.fullMatch(sheet, nodeChild, child) {
 			return false
 		}
+		present[nodeChild] = true
 	}
 
-	return true
-}
-
-// TODO
-func (r *Reader[T]) setStructValue(t *T, kind reflect.Kind, paths []string, value string) error {
-	var valueOf reflect.Value
-	var typeOf reflect.Type
-	valueOf = reflect.ValueOf(t).Elem()
-	typeOf = reflect.TypeOf(t).Elem()
-
-	for i := 0; i < len(paths); i++ {
-		for {
-			if typeOf.Kind() == reflect.Ptr {
-				typeOf = typeOf.Elem()
-				continue
-			}
-			break
-		}
-
-		_, ok := typeOf.FieldByName(paths[i])
-		if !ok {
-			return fmt.Errorf("A - path [%s] is not valid, full path is: [%s], value is: [%s]", strings.Join(paths[0:i+1], "->"), strings.Join(paths, "->"), value)
+	for _, child := range node.Children {
+		if !present[child] && !child.IsOptional() {
+			return false
 		}
+	}
 
-		if valueOf.Kind() == reflect.Ptr && valueOf.IsNil() {
-			valueOf = reflect.New(typeOf).Elem()
-		}
+	return true
+}
 
-		valueOf = valueOf.FieldByName(paths[i])
-		if !valueOf.IsValid() {
-			return fmt.Errorf("B - path [%s] is not valid, full path is: [%s], value is: [%s]", strings.Join(paths[0:i+1], "->"), strings.Join(paths, "->"), value)
-		}
-		typeOf = valueOf.Type()
+// setField
+// 将单元格的值转换后写入叶子节点对应的字段
+// 字段通过解析时记录的索引路径定位，只读的列被忽略
+func (r *Reader[T]) setField(t *T, meta *Meta, value string) error {
+	if meta.Node.readOnly {
+		return nil
 	}
 
-	if valueOf.Kind() != kind {
-		return fmt.Errorf("path [%s] is no valid: expected [%s], [%s] read from struct [%T]", strings.Join(paths, "->"), kind, valueOf.Kind(), t)
-	}
+	valueOf := reflect.ValueOf(t).Elem().FieldByIndex(meta.Node.fieldIndex)
 
-	switch kind {
+	switch meta.Kind {
 	case reflect.Int,
 		reflect.Int8,
 		reflect.Int16,
 		reflect.Int32,
 		reflect.Int64:
-		intValue, err := strconv.ParseInt(value, 10, 64)
+		intValue, err := strconv.ParseInt(value, 10, valueOf.Type().Bits())
 		if err != nil {
-			err = fmt.Errorf("value of path [%s] no set: %s", strings.Join(paths, "->"), err.Error())
+			return fmt.Errorf("value of path [%s] no set: %s", strings.Join(meta.Paths, "->"), err.Error())
 		}
 		valueOf.SetInt(intValue)
 	case reflect.Float32,
 		reflect.Float64:
-		floatValue, err := strconv.ParseFloat(value, 64)
+		floatValue, err := strconv.ParseFloat(value, valueOf.Type().Bits())
 		if err != nil {
-			err = fmt.Errorf("value of path [%s] no set: %s", strings.Join(paths, "->"), err.Error())
+			return fmt.Errorf("value of path [%s] no set: %s", strings.Join(meta.Paths, "->"), err.Error())
 		}
 		valueOf.SetFloat(floatValue)
 	case reflect.Uint,
@@ -234,13 +478,21 @@ func (r *Reader[T]) setStructValue(t *T, kind reflect.Kind, paths []string, valu
 		reflect.Uint16,
 		reflect.Uint32,
 		reflect.Uint64:
-		intValue, err := strconv.ParseUint(value, 10, 64)
+		intValue, err := strconv.ParseUint(value, 10, valueOf.Type().Bits())
 		if err != nil {
-			err = fmt.Errorf("value of path [%s] no set: %s", strings.Join(paths, "->"), err.Error())
+			return fmt.Errorf("value of path [%s] no set: %s", strings.Join(meta.Paths, "->"), err.Error())
 		}
 		valueOf.SetUint(intValue)
-	default:
+	case reflect.Bool:
+		boolValue, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("value of path [%s] no set: %s", strings.Join(meta.Paths, "->"), err.Error())
+		}
+		valueOf.SetBool(boolValue)
+	case reflect.String:
 		valueOf.SetString(value)
+	default:
+		return fmt.Errorf("value of path [%s] no set: unsupported kind %s", strings.Join(meta.Paths, "->"), meta.Kind)
 	}
 
 	return nil
@@ -0,0 +1,45 @@
+package dynamic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type overflowItem struct {
+	Small int8    `xlsx:"col:小"`
+	Count uint8   `xlsx:"col:数量"`
+	Rate  float32 `xlsx:"col:比例"`
+}
+
+func TestReadOverflow(t *testing.T) {
+	reader, err := NewReader[overflowItem]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		cell  string
+		value string
+	}{
+		{"A2", "300"},
+		{"B2", "256"},
+		{"C2", "1e40"},
+	} {
+		file := excelize.NewFile()
+		for cell, value := range map[string]string{"A1": "小", "B1": "数量", "C1": "比例", "A2": "1", "B2": "1", "C2": "1"} {
+			if err := file.SetCellStr("Sheet1", cell, value); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := file.SetCellStr("Sheet1", c.cell, c.value); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := reader.Read(file, "Sheet1")
+		if err == nil || !strings.Contains(err.Error(), "out of range") {
+			t.Errorf("%s=%s: got error %v, want out of range", c.cell, c.value, err)
+		}
+	}
+}
@@ -7,56 +7,171 @@ import (
 	"github.com/xuri/excelize/v2"
 )
 
-func NewRenderer[T any](file *excelize.File, sheet string) (*Renderer[T], error) {
+// RendererOption
+// 渲染器配置
+type RendererOption func(*rendererOptions)
+
+type rendererOptions struct {
+	zeroPlaceholder *string
+	theme           Theme
+	footerLabel     string
+}
+
+// WithTheme
+// 指定渲染样式，默认使用DefaultTheme
+func WithTheme(theme Theme) RendererOption {
+	return func(o *rendererOptions) {
+		o.theme = theme
+	}
+}
+
+// WithZeroPlaceholder
+// 零值渲染为占位符，占位符为空字符串时渲染为空白
+// 设置了default标签的列仍然渲染零值，保证读取时不会被默认值替换
+func WithZeroPlaceholder(placeholder string) RendererOption {
+	return func(o *rendererOptions) {
+		o.zeroPlaceholder = &placeholder
+	}
+}
+
+// NewRenderer
+// 创建渲染到excel文件中指定sheet的渲染器
+func NewRenderer[T any](file *excelize.File, sheet string, opts ...RendererOption) (*Renderer[T], error) {
+	options := newRendererOptions(opts)
+
+	return NewBackendRenderer[T](NewExcelizeBackend(file, sheet, options.theme), opts...)
+}
+
+// NewBackendRenderer
+// 创建渲染到指定后端的渲染器，WithTheme只对默认的excelize后端生效
+func NewBackendRenderer[T any](backend Backend, opts ...RendererOption) (*Renderer[T], error) {
 	var t T
 	if reflect.TypeOf(t).Kind() != reflect.Struct {
 		return nil, fmt.Errorf("type of %T is not struct", t)
 	}
 
+	parser := Parser[T]{}
+	tree, err := parser.Parse()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Renderer[T]{
-		file:  file,
-		sheet: sheet,
+		backend: backend,
+		parser:  &parser,
+		tree:    tree,
+		options: newRendererOptions(opts),
 	}, nil
 }
 
-type Renderer[T any] struct {
-	file   *excelize.File
-	sheet  string
-	parser *Parser[T]
-	tree   *Tree[T]
+func newRendererOptions(opts []RendererOption) rendererOptions {
+	options := rendererOptions{theme: DefaultTheme}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
 }
 
-func (r *Renderer[T]) Render(data []T) {
-	if r.parser == nil {
-		r.parser = &Parser[T]{}
+type Renderer[T any] struct {
+	backend Backend
+	parser  *Parser[T]
+	tree    *Tree[T]
+	options rendererOptions
+
+	// columns
+	// 注册的计算列
+	columns []Column[T]
 
-		tree, _ := r.parser.Parse()
-		r.tree = tree
-		r.tree.ParseValues(data)
+	// rendered
+	// 上一次渲染的数据和汇总行所占的区域，没有渲染过数据时为零值
+	rendered Area
+}
+
+// Render
+// 渲染表头、数据和汇总行，每次调用都使用传入的数据，同一个渲染器可以多次渲染
+// 多次渲染到同一个后端时，先清空上一次渲染的数据和汇总行
+func (r *Renderer[T]) Render(data []T) error {
+	if r.rendered.EndY > 0 {
+		if err := r.backend.Clear(r.rendered.StartX, r.rendered.StartY, r.rendered.EndX, r.rendered.EndY); err != nil {
+			return err
+		}
+		r.rendered = Area{}
 	}
 
-	r.file.NewSheet(r.sheet)
-	headStyleId, _ := r.file.NewStyle(&headerStyle)
-	bodyStyleId, _ := r.file.NewStyle(&bodyStyle)
+	if err := renderHeader(r.backend, r.tree.Metas()); err != nil {
+		return err
+	}
 
-	for _, meta := range r.tree.metas {
-		start := fmt.Sprintf("%s%d", numberToLetters(meta.StartX), meta.StartY)
-		end := fmt.Sprintf("%s%d", numberToLetters(meta.EndX), meta.EndY)
-		if start != end {
-			r.file.MergeCell(r.sheet, start, end)
+	// 设置了agg的列写入的值，用于计算汇总行
+	var written = map[*Node][]any{}
+	for _, meta := range r.tree.ParseValues(data) {
+		for cur, value := range meta.rows {
+			x, y := meta.StartX, meta.EndY+cur+1
+			var cell = value.Value
+			if r.isPlaceholder(meta, value) {
+				cell = *r.options.zeroPlaceholder
+			}
+			if err := r.backend.SetValue(x, y, cell); err != nil {
+				return err
+			}
+			if meta.Node.Agg != "" {
+				written[meta.Node] = append(written[meta.Node], cell)
+			}
+			// 字段的值作为公式缓存的计算结果
+			if f := meta.Node.formula; f != nil {
+				if err := r.backend.SetFormula(x, y, f.render(y)); err != nil {
+					return err
+				}
+			}
+			if err := r.backend.SetStyle(x, y, x, y, BodyStyle); err != nil {
+				return err
+			}
 		}
-		r.file.SetCellStr(r.sheet, start, meta.Node.Title)
-		r.file.SetCellStyle(r.sheet, start, end, headStyleId)
+	}
+
+	if err := r.renderFooter(len(data), written); err != nil {
+		return err
+	}
 
-		if len(meta.rows) == 0 {
-			continue
+	if len(data) > 0 {
+		startY := r.tree.MaxLevel() + 1
+		endY := startY + len(data) - 1
+		if r.tree.hasFooter() {
+			endY++
 		}
+		r.rendered = Area{StartX: 1, StartY: startY, EndX: len(r.tree.Leaves()), EndY: endY}
+	}
+
+	return nil
+}
 
-		meta.Render(func(coor string, value TypedValue) error {
-			if err := r.file.SetCellValue(r.sheet, coor, value.Value); err != nil {
+// isPlaceholder
+// 判断是否使用占位符代替零值渲染
+func (r *Renderer[T]) isPlaceholder(meta *Meta, value TypedValue) bool {
+	if r.options.zeroPlaceholder == nil || meta.Node.Default != "" {
+		return false
+	}
+
+	return value.Value == nil || reflect.ValueOf(value.Value).IsZero()
+}
+
+// renderHeader
+// 渲染表头，合并节点所占的单元格
+func renderHeader(backend Backend, metas []*Meta) error {
+	for _, meta := range metas {
+		if meta.StartX != meta.EndX || meta.StartY != meta.EndY {
+			if err := backend.MergeCell(meta.StartX, meta.StartY, meta.EndX, meta.EndY); err != nil {
 				return err
 			}
-			return r.file.SetCellStyle(r.sheet, coor, coor, bodyStyleId)
-		})
+		}
+		if err := backend.SetValue(meta.StartX, meta.StartY, meta.Node.Title); err != nil {
+			return err
+		}
+		if err := backend.SetStyle(meta.StartX, meta.StartY, meta.EndX, meta.EndY, HeaderStyle); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
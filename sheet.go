@@ -2,6 +2,8 @@ package dynamic
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 
 	"github.com/xuri/excelize/v2"
 )
@@ -55,6 +57,7 @@ func (c *Sheet[T]) values() (map[string]*CellValue, error) {
 	}
 
 	curY := 0
+	lastY := 0
 	for rows.Next() {
 		curY++
 
@@ -63,25 +66,31 @@ func (c *Sheet[T]) values() (map[string]*CellValue, error) {
 			continue
 		}
 
+		y := strconv.Itoa(curY)
 		size := len(cols)
 		for i := 0; i < size; i++ {
+			if cols[i] != "" {
+				lastY = curY
+			}
+
 			value := CellValue{
 				X:     i + 1,
 				Y:     curY,
 				Value: cols[i],
 			}
 
-			key := fmt.Sprintf("%s%d", numberToLetters(value.X), value.Y)
-			c.mapdValues[key] = &value
+			c.mapdValues[numberToLetters(value.X)+y] = &value
 		}
 	}
-	c.depth = curY
+	// 末尾只有样式或者被清空的行不计入行数
+	c.depth = lastY
 
 	for key, aliasKey := range c.aliasCells {
 		cell, ok := c.mapdValues[key]
 		if !ok {
 			// 自动填充
 			cell = &CellValue{}
+			cell.X, cell.Y, _ = excelize.CellNameToCoordinates(key)
 			c.mapdValues[key] = cell
 		}
 		alias, ok := c.mapdValues[aliasKey]
@@ -194,3 +203,107 @@ func (c *Sheet[T]) GetHeaderCellValues() []*CellValue {
 
 	return cellValues
 }
+
+// HeaderColumns
+// 获取表头中所有非空列的X坐标，按从左到右排序
+func (c *Sheet[T]) HeaderColumns() []int {
+	var seen = map[int]bool{}
+	for _, cellValue := range c.mapdValues {
+		if cellValue.Y > c.headerLevel {
+			continue
+		}
+		if cellValue.Alias != nil {
+			seen[cellValue.X] = seen[cellValue.X] || cellValue.Alias.Value != ""
+			continue
+		}
+		seen[cellValue.X] = seen[cellValue.X] || cellValue.Value != ""
+	}
+
+	var columns = make([]int, 0, len(seen))
+	for x, ok := range seen {
+		if ok {
+			columns = append(columns, x)
+		}
+	}
+	sort.Ints(columns)
+
+	return columns
+}
+
+// HeaderPath
+// 获取某一列的表头路径，从上到下排列，合并单元格只取一次
+func (c *Sheet[T]) HeaderPath(x int) []string {
+	var paths = []string{}
+	var last *CellValue
+	for y := 1; y <= c.headerLevel; y++ {
+		cellValue, ok := c.mapdValues[fmt.Sprintf("%s%d", numberToLetters(x), y)]
+		if !ok {
+			continue
+		}
+		if cellValue.Alias != nil {
+			cellValue = cellValue.Alias
+		}
+		if cellValue == last || cellValue.Value == "" {
+			continue
+		}
+		last = cellValue
+		paths = append(paths, cellValue.Value)
+	}
+
+	return paths
+}
+
+// inferHeaderLevel
+// 根据合并单元格推断表头的行数
+// 从第一行开始，纵向合并的单元格延伸到合并的最后一行，横向合并的单元格下方还有一行子节点
+func (c *Sheet[T]) inferHeaderLevel() int {
+	type area struct {
+		startY int
+		endX   int
+		endY   int
+		startX int
+	}
+
+	var areas = map[string]*area{}
+	for key, aliasKey := range c.aliasCells {
+		x, y, err := excelize.CellNameToCoordinates(key)
+		if err != nil {
+			continue
+		}
+		a, ok := areas[aliasKey]
+		if !ok {
+			startX, startY, err := excelize.CellNameToCoordinates(aliasKey)
+			if err != nil {
+				continue
+			}
+			a = &area{startX: startX, startY: startY, endX: startX, endY: startY}
+			areas[aliasKey] = a
+		}
+		if x > a.endX {
+			a.endX = x
+		}
+		if y > a.endY {
+			a.endY = y
+		}
+	}
+
+	level := 1
+	for changed := true; changed; {
+		changed = false
+		for _, a := range areas {
+			if a.startY > level {
+				continue
+			}
+			end := a.endY
+			if a.endX > a.startX {
+				end++
+			}
+			if end > level {
+				level = end
+				changed = true
+			}
+		}
+	}
+
+	return level
+}
@@ -58,3 +58,73 @@ var bodyStyle = excelize.Style{
 		WrapText:        true,
 	},
 }
+
+var footerStyle = excelize.Style{
+	Font: &excelize.Font{
+		Bold:   true,
+		Family: "Times New Roman",
+		Size:   12,
+		Color:  "#000000",
+	},
+	Border: []excelize.Border{{
+		Type:  "top",
+		Color: "333333",
+		Style: 6,
+	}},
+	Alignment: &excelize.Alignment{
+		Horizontal: "center",
+		Vertical:   "center",
+	},
+}
+
+// Theme
+// 渲染样式，分别用于表头、数据和汇总行
+type Theme struct {
+	Header excelize.Style
+	Body   excelize.Style
+	Footer excelize.Style
+}
+
+// DefaultTheme
+// 默认渲染样式
+var DefaultTheme = Theme{
+	Header: headerStyle,
+	Body:   bodyStyle,
+	Footer: footerStyle,
+}
+
+// styles
+// 在文件中创建的样式ID
+// 同一个文件中的多个渲染器可以共享，避免每个sheet重复创建样式
+type styles struct {
+	theme   Theme
+	created bool
+	header  int
+	body    int
+	footer  int
+}
+
+func newStyles(theme Theme) *styles {
+	return &styles{theme: theme}
+}
+
+// ids
+// 获取表头、数据和汇总行的样式ID，第一次调用时在文件中创建样式
+func (s *styles) ids(file *excelize.File) (header int, body int, footer int, err error) {
+	if s.created {
+		return s.header, s.body, s.footer, nil
+	}
+
+	if s.header, err = file.NewStyle(&s.theme.Header); err != nil {
+		return
+	}
+	if s.body, err = file.NewStyle(&s.theme.Body); err != nil {
+		return
+	}
+	if s.footer, err = file.NewStyle(&s.theme.Footer); err != nil {
+		return
+	}
+	s.created = true
+
+	return s.header, s.body, s.footer, nil
+}
@@ -0,0 +1,99 @@
+package dynamic
+
+import (
+	"strings"
+)
+
+const (
+	tagName        = "xlsx"
+	aliasSeparator = "|"
+)
+
+// tagOptions
+// xlsx标签解析结果
+// 标签格式: `xlsx:"col:标题,alias:别名1|别名2"`，多个选项之间用逗号分隔
+type tagOptions struct {
+	// ignore
+	// 标签为"-"时忽略该字段
+	ignore bool
+
+	// col
+	// 列标题
+	col string
+
+	// alias
+	// 列标题的别名，读取时可以匹配任意一个别名，多个别名用"|"分隔
+	alias []string
+
+	// optional
+	// 可选列，读取时excel中可以不存在该列
+	optional bool
+
+	// defaultValue
+	// 读取时单元格为空或者列不存在时使用的默认值
+	defaultValue string
+
+	// extra
+	// 接收未匹配的列，字段类型必须是map[string]string
+	extra bool
+
+	// include
+	// 未导出的字段默认被忽略，设置include后作为只读列渲染
+	include bool
+
+	// method
+	// 列的值由字段所在结构体的方法计算，例如`xlsx:"col:总分,method:Total"`
+	// 方法没有参数并且只有一个返回值，该列是只读的，读取时被忽略
+	// 设置了method的未导出字段不需要再设置include
+	method string
+
+	// agg
+	// 在数据下方的汇总行中对该列使用的汇总函数，可以是sum、avg、count、min、max
+	agg string
+
+	// formula
+	// 渲染为excel公式，用"{}"引用同一个结构体中的字段，例如`xlsx:"col:最终成绩,formula:{Normal}*0.3+{Examing}*0.7"`
+	// 公式中可以有逗号，所以formula必须是最后一个选项
+	formula string
+}
+
+func parseTag(tag string) tagOptions {
+	opts := tagOptions{}
+	if tag == ignore {
+		opts.ignore = true
+		return opts
+	}
+
+	for tag != "" {
+		var c string
+		c, tag, _ = strings.Cut(tag, ",")
+		key, value, _ := strings.Cut(c, ":")
+		switch key {
+		case "col":
+			opts.col = value
+		case "alias":
+			opts.alias = strings.Split(value, aliasSeparator)
+		case "default":
+			opts.defaultValue = value
+		case "optional":
+			opts.optional = true
+		case "extra":
+			opts.extra = true
+		case "include":
+			opts.include = true
+		case "method":
+			opts.method = value
+		case "agg":
+			opts.agg = value
+		case "formula":
+			// 公式包含之后所有的内容
+			if tag != "" {
+				value = value + "," + tag
+			}
+			opts.formula = value
+			tag = ""
+		}
+	}
+
+	return opts
+}
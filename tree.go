@@ -1,103 +1,26 @@
 package dynamic
 
 import (
-	"strings"
+	"reflect"
+	"sort"
 )
 
 type Tree[T any] struct {
 	Nodes    []*Node
 	metas    []*Meta
 	maxLevel int
-}
-
-// offsetX
-// 获取一个节点在一棵树中的X偏移
-func (t *Tree[T]) offsetX(node *Node) int {
-	if node == nil {
-		return 1
-	}
-
-	prev := t.GetPrev(node)
-	if prev == nil {
-		return t.offsetX(node.parent)
-	}
-
-	return t.offsetX(prev) + prev.Cols()
-}
-
-// paths
-// 获取一个节点的路径
-// 路径组成是一个数组，这个数组是一个按层级递增的的
-// 例如一个结构体如下：
-/*
-   type A struct {
-      Field string `xlsx:"col:字段"`
-   }
-   type B struct {
-      BField string `xlsx:"col:另一个字段`
-      AField A `xlsx:"col:原来的字段`
-   }
-
-   // 实际渲染的内容
-   type C struct {
-      CField int `xlsx:"col:C字段"`
-      BField B `xlsx:"col:B字段"`
-   }
-*/
-// 展开C结构体如下：
-/*
- C:
-   CField:
-     BField:
-       BField
-       AField:
-         A
-*/
-// 那么A的路径就是[CField, BField, AField, A]
-func (t *Tree[T]) paths(node *Node) []string {
-	if node == nil {
-		return []string{}
-	}
-	paths := []string{node.Field}
-	paths = append(t.paths(t.GetParent(node)), paths...)
-
-	return paths
-}
-
-func (t *Tree[T]) meta(node *Node) *Meta {
-	if node == nil {
-		return nil
-	}
 
-	meta := &Meta{
-		Node:   node,
-		Paths:  t.paths(node),
-		StartX: t.OffsetX(node),
-		StartY: node.Y(),
-		Kind:   node.Kind,
-	}
+	// leaves
+	// 按列坐标排序的叶子节点元数据
+	leaves []*Meta
 
-	meta.EndX = meta.StartX + meta.Node.Cols() - 1
-	meta.EndY = meta.StartY
+	// extra
+	// 接收未匹配列的字段名
+	extra string
 
-	return meta
-}
-
-func (t *Tree[T]) childrenMetas(node *Node) []*Meta {
-	if node == nil {
-		return nil
-	}
-
-	metas := []*Meta{}
-	for _, child := range node.Children {
-		childMeta := t.meta(child)
-		metas = append(metas, childMeta)
-		if len(node.Children) > 0 {
-			metas = append(metas, t.childrenMetas(child)...)
-		}
-	}
-
-	return metas
+	// codec
+	// 类型T上由dynamicgen生成的编解码方法
+	codec codec[T]
 }
 
 func (t *Tree[T]) GetParent(node *Node) *Node {
@@ -163,29 +86,48 @@ func (t *Tree[T]) GetNext(node *Node) *Node {
 	return nil
 }
 
-func (t Tree[T]) OffsetX(node *Node) int {
-	if node.offsetX == 0 {
-		node.offsetX = t.offsetX(node)
-	}
+// OffsetX
+// 获取一个节点在一棵树中的X偏移
+func (t *Tree[T]) OffsetX(node *Node) int {
+	t.Metas()
 
 	return node.offsetX
 }
 
 // Metas
-// 获取每一个元素的可渲染元数据
+// 获取每一个元素的可渲染元数据，顺序为深度优先的先序遍历
+// 所有节点的坐标在一次遍历中计算完成
 func (t *Tree[T]) Metas() []*Meta {
 	if t.metas != nil {
 		return t.metas
 	}
 
-	var metas []*Meta
+	metas := []*Meta{}
+	x := 1
+
+	var walk func(nodes []*Node, paths []string)
+	walk = func(nodes []*Node, paths []string) {
+		for _, node := range nodes {
+			meta := &Meta{
+				Node:   node,
+				Paths:  append(paths[:len(paths):len(paths)], node.Field),
+				StartX: x,
+				StartY: node.Y(),
+				EndY:   node.Y(),
+				Kind:   node.Kind,
+			}
+			node.offsetX = x
+			metas = append(metas, meta)
 
-	for _, node := range t.Nodes {
-		nodeMeta := t.meta(node)
-		nodeChilrenMetas := t.childrenMetas(node)
-		metas = append(metas, nodeMeta)
-		metas = append(metas, nodeChilrenMetas...)
+			if len(node.Children) == 0 {
+				x++
+			} else {
+				walk(node.Children, meta.Paths)
+			}
+			meta.EndX = x - 1
+		}
 	}
+	walk(t.Nodes, nil)
 	t.metas = metas
 
 	for _, meta := range metas {
@@ -213,33 +155,40 @@ func (t *Tree[T]) MaxLevel() int {
 	return t.maxLevel
 }
 
-func (t *Tree[T]) ParseValues(data []T) {
-	size := len(data)
-	res := make(map[string][]TypedValue)
-	for _, meta := range t.Metas() {
-		if meta.Node.Level < t.MaxLevel() && len(meta.Node.Children) > 0 {
-			continue
-		}
-
-		res[strings.Join(meta.Paths, ".")] = make([]TypedValue, len(data))
+// ParseValues
+// 按叶子节点获取每一列的数据
+// 返回的是叶子节点元数据的副本，数据只保存在副本中，不会修改解析树
+func (t *Tree[T]) ParseValues(data []T) []*Meta {
+	leaves := t.Leaves()
+	metas := make([]*Meta, len(leaves))
+	for i, leaf := range leaves {
+		meta := *leaf
+		meta.rows = make([]TypedValue, len(data))
+		metas[i] = &meta
 	}
 
-	for cur := 0; cur < size; cur++ {
-		values := getFieldsValue(data[cur])
-		for k, v := range values {
-			res[k][cur] = v
+	row := make([]any, len(leaves))
+	for cur := range data {
+		for i, value := range t.encodeRow(data[cur], row) {
+			meta := metas[i]
+			meta.rows[cur] = TypedValue{
+				Paths: meta.Paths,
+				Kind:  meta.Kind,
+				Value: value,
+			}
 		}
 	}
 
-	for _, meta := range t.metas {
-		if meta.Node.Level < t.MaxLevel() && len(meta.Node.Children) > 0 {
-			continue
-		}
-		key := strings.Join(meta.Paths, ".")
-		if values, ok := res[key]; ok {
-			meta.rows = values
-		}
-	}
+	return metas
+}
+
+// freeze
+// 完成所有延迟的计算，包括节点偏移、元数据、叶子节点和生成的编解码方法
+// 之后解析树只读，可以在多个goroutine中共享
+func (t *Tree[T]) freeze() {
+	t.Metas()
+	t.Leaves()
+	t.generated()
 }
 
 func (t *Tree[T]) ToCellValues() []*CellValue {
@@ -290,3 +239,47 @@ func (t *Tree[T]) FindNodesByTag(level int, tag string) (res []*Node) {
 
 	return
 }
+
+// Leaves
+// 获取所有叶子节点的元数据，即实际存放数据的列，按列坐标排序
+func (t *Tree[T]) Leaves() []*Meta {
+	if t.leaves != nil {
+		return t.leaves
+	}
+
+	var leaves []*Meta
+	for _, meta := range t.Metas() {
+		if len(meta.Node.Children) == 0 {
+			leaves = append(leaves, meta)
+		}
+	}
+
+	sort.Slice(leaves, func(i, j int) bool {
+		return leaves[i].StartX < leaves[j].StartX
+	})
+	t.leaves = leaves
+
+	return t.leaves
+}
+
+// RowValues
+// 按叶子节点的顺序获取一行数据中每一列的值
+func (t *Tree[T]) RowValues(data T) []any {
+	return t.encodeRow(data, make([]any, len(t.Leaves())))
+}
+
+// encodeRow
+// 按叶子节点的顺序将一行数据的值写入values
+// 没有生成的EncodeRow时，通过解析时记录的字段索引读取，不再逐层查找字段
+func (t *Tree[T]) encodeRow(data T, values []any) []any {
+	if t.generated().encoder {
+		return any(data).(RowEncoder).EncodeRow()
+	}
+
+	valueOf := reflect.ValueOf(&data).Elem()
+	for i, meta := range t.Leaves() {
+		values[i] = meta.Node.value(valueOf)
+	}
+
+	return values
+}
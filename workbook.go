@@ -0,0 +1,217 @@
+package dynamic
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// defaultSheet
+// excelize新建文件时自带的sheet
+const defaultSheet = "Sheet1"
+
+// Workbook
+// 在一个工作簿中渲染和读取多个不同类型的sheet
+// 所有sheet共享同一个文件和样式
+type Workbook struct {
+	file    *excelize.File
+	created bool
+	styles  *styles
+	sheets  []workbookSheet
+}
+
+// workbookSheet
+// 注册到工作簿中的sheet
+type workbookSheet interface {
+	name() string
+	render() error
+	read() error
+}
+
+// WorkbookOption
+// 工作簿配置
+type WorkbookOption func(*workbookOptions)
+
+type workbookOptions struct {
+	theme Theme
+}
+
+// WithWorkbookTheme
+// 指定工作簿中所有sheet的渲染样式，默认使用DefaultTheme
+func WithWorkbookTheme(theme Theme) WorkbookOption {
+	return func(o *workbookOptions) {
+		o.theme = theme
+	}
+}
+
+// NewWorkbook
+// 创建工作簿，file为nil时创建一个新文件
+func NewWorkbook(file *excelize.File, opts ...WorkbookOption) *Workbook {
+	options := workbookOptions{theme: DefaultTheme}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	created := false
+	if file == nil {
+		file = excelize.NewFile()
+		created = true
+	}
+
+	return &Workbook{
+		file:    file,
+		created: created,
+		styles:  newStyles(options.theme),
+	}
+}
+
+// File
+// 获取工作簿对应的excel文件
+func (w *Workbook) File() *excelize.File {
+	return w.file
+}
+
+// SheetOption
+// 注册sheet时的配置
+type SheetOption func(*sheetOptions)
+
+type sheetOptions struct {
+	name     string
+	renderer []RendererOption
+	reader   []ReaderOption
+}
+
+// WithSheetName
+// 指定Marshal和Unmarshal使用的sheet名称，Register时以参数中的名称为准
+func WithSheetName(name string) SheetOption {
+	return func(o *sheetOptions) {
+		o.name = name
+	}
+}
+
+// WithRendererOptions
+// 注册sheet时使用的渲染器配置，样式由工作簿统一指定
+func WithRendererOptions(opts ...RendererOption) SheetOption {
+	return func(o *sheetOptions) {
+		o.renderer = append(o.renderer, opts...)
+	}
+}
+
+// WithReaderOptions
+// 注册sheet时使用的读取器配置
+func WithReaderOptions(opts ...ReaderOption) SheetOption {
+	return func(o *sheetOptions) {
+		o.reader = append(o.reader, opts...)
+	}
+}
+
+// Register
+// 将一个类型的数据注册到工作簿中的sheet
+// 渲染时写入data中的数据，读取时将sheet中的数据写入data
+func Register[T any](w *Workbook, sheet string, data *[]T, opts ...SheetOption) error {
+	for _, registered := range w.sheets {
+		if registered.name() == sheet {
+			return fmt.Errorf("sheet %s is already registered", sheet)
+		}
+	}
+
+	options := sheetOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	backend := &ExcelizeBackend{
+		file:   w.file,
+		sheet:  sheet,
+		styles: w.styles,
+	}
+	renderer, err := NewBackendRenderer[T](backend, options.renderer...)
+	if err != nil {
+		return err
+	}
+
+	reader, err := NewReader[T](options.reader...)
+	if err != nil {
+		return err
+	}
+
+	w.sheets = append(w.sheets, &boundSheet[T]{
+		sheet:    sheet,
+		data:     data,
+		file:     w.file,
+		renderer: renderer,
+		reader:   reader,
+	})
+
+	return nil
+}
+
+// Render
+// 按注册顺序渲染所有sheet
+// 新建的文件中如果没有注册默认的Sheet1，那么删除Sheet1
+func (w *Workbook) Render() error {
+	registered := false
+	for _, sheet := range w.sheets {
+		if err := sheet.render(); err != nil {
+			return err
+		}
+		registered = registered || sheet.name() == defaultSheet
+	}
+
+	if w.created && !registered && len(w.sheets) > 0 {
+		if err := w.file.DeleteSheet(defaultSheet); err != nil {
+			return err
+		}
+		idx, err := w.file.GetSheetIndex(w.sheets[0].name())
+		if err != nil {
+			return err
+		}
+		w.file.SetActiveSheet(idx)
+	}
+
+	return nil
+}
+
+// Read
+// 读取所有注册的sheet，读取失败的sheet通过SheetErrors返回
+func (w *Workbook) Read() error {
+	var errs SheetErrors
+	for _, sheet := range w.sheets {
+		if err := sheet.read(); err != nil {
+			errs = append(errs, &SheetError{Sheet: sheet.name(), Err: err})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// boundSheet
+// 类型T和sheet的绑定关系
+type boundSheet[T any] struct {
+	sheet    string
+	data     *[]T
+	file     *excelize.File
+	renderer *Renderer[T]
+	reader   *Reader[T]
+}
+
+func (s *boundSheet[T]) name() string {
+	return s.sheet
+}
+
+func (s *boundSheet[T]) render() error {
+	return s.renderer.Render(*s.data)
+}
+
+func (s *boundSheet[T]) read() error {
+	values, err := s.reader.Read(s.file, s.sheet)
+	if values != nil {
+		*s.data = values
+	}
+
+	return err
+}